@@ -0,0 +1,327 @@
+// Package cli is the gographer command line tool, working on the schemas registered by the main package:
+//
+//	func main() {
+//		cli.RegisterSchema("todo", data.GetModelSchemaInfo)
+//		cli.Main()
+//	}
+//
+// Commands:
+//
+//	schema [-schema name] [-format json|sdl] [-o file]   write introspection JSON or SDL
+//	inspect [-schema name]                               list GraphQL fields with their Go fields and methods
+//	query [-schema name] [-variables json] [-operation name] [file]   run an operation from file or stdin
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/testutil"
+	"github.com/xinhuang327/gographer"
+	"golang.org/x/net/context"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type schemaEntry struct {
+	name  string
+	build func() *gographer.SchemaInfo
+}
+
+var schemas []schemaEntry
+
+// RegisterSchema makes a schema available to the commands, call it from main or an init function
+// before Main. build is called once per command.
+func RegisterSchema(name string, build func() *gographer.SchemaInfo) {
+	schemas = append(schemas, schemaEntry{name, build})
+}
+
+// Main runs the command given by os.Args and exits with its status.
+func Main() {
+	os.Exit(Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Run runs a command with arguments, returns the exit status: 0 on success, 1 on errors, 2 on usage errors.
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	var cmd func(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int
+	switch args[0] {
+	case "schema":
+		cmd = schemaCmd
+	case "inspect":
+		cmd = inspectCmd
+	case "query":
+		cmd = queryCmd
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `usage: gographer <command> [flags]
+
+commands:
+  schema    write the schema as introspection JSON or SDL
+  inspect   list GraphQL fields with the Go fields and methods resolving them
+  query     run a query or mutation from a file or stdin
+
+run gographer <command> -h for the flags of a command
+`)
+	if len(schemas) > 0 {
+		fmt.Fprintf(w, "\nschemas: %s\n", strings.Join(schemaNames(), ", "))
+	}
+}
+
+func schemaNames() []string {
+	var names []string
+	for _, entry := range schemas {
+		names = append(names, entry.name)
+	}
+	return names
+}
+
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *string) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	schemaName := flags.String("schema", "", "name of the registered schema, needed if more than one is registered")
+	return flags, schemaName
+}
+
+// Schema info registered with name, or the only one registered if name is empty.
+func lookupSchema(name string) (*gographer.SchemaInfo, error) {
+	if len(schemas) == 0 {
+		return nil, errors.New("no schema registered, call cli.RegisterSchema in main")
+	}
+	if name == "" {
+		if len(schemas) > 1 {
+			return nil, fmt.Errorf("more than one schema registered, use -schema with one of %s", strings.Join(schemaNames(), ", "))
+		}
+		return schemas[0].build(), nil
+	}
+	for _, entry := range schemas {
+		if entry.name == name {
+			return entry.build(), nil
+		}
+	}
+	return nil, fmt.Errorf("schema %q is not registered, registered: %s", name, strings.Join(schemaNames(), ", "))
+}
+
+// Built schema, registration errors are reported on stderr but don't stop the command
+// unless no schema could be built, e.g. in strict mode.
+func buildSchema(name string, stderr io.Writer) (graphql.Schema, bool) {
+	schemaInfo, err := lookupSchema(name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return graphql.Schema{}, false
+	}
+	schema, err := schemaInfo.GetSchema()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+	return schema, schema.QueryType() != nil
+}
+
+func schemaCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags, schemaName := newFlagSet("schema", stderr)
+	format := flags.String("format", "json", "output format, json (introspection result) or sdl")
+	output := flags.String("o", "", "output file, stdout if empty")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "json" && *format != "sdl" {
+		fmt.Fprintf(stderr, "unknown format %q, use json or sdl\n", *format)
+		return 2
+	}
+	schema, ok := buildSchema(*schemaName, stderr)
+	if !ok {
+		return 1
+	}
+
+	var out []byte
+	if *format == "sdl" {
+		out = []byte(gographer.PrintSchema(schema))
+	} else {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: testutil.IntrospectionQuery,
+		})
+		if result.HasErrors() {
+			fmt.Fprintf(stderr, "introspecting schema: %v\n", result.Errors)
+			return 1
+		}
+		var err error
+		if out, err = stableJSON(result); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	if *output == "" {
+		stdout.Write(out)
+		return 0
+	}
+	if err := ioutil.WriteFile(*output, out, 0644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// Indented JSON with lists of named items (types, fields, enum values...) sorted by name,
+// graphql-go keeps them in maps, so their order changes between runs.
+func stableJSON(v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+	sortByName(generic)
+	out, err := json.MarshalIndent(generic, "", "  ")
+	return append(out, '\n'), err
+}
+
+func sortByName(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			sortByName(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			sortByName(item)
+		}
+		name := func(i int) (string, bool) {
+			obj, ok := v[i].(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			name, ok := obj["name"].(string)
+			return name, ok
+		}
+		for i := range v {
+			if _, ok := name(i); !ok {
+				return
+			}
+		}
+		sort.SliceStable(v, func(i, j int) bool {
+			nameI, _ := name(i)
+			nameJ, _ := name(j)
+			return nameI < nameJ
+		})
+	}
+}
+
+func inspectCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags, schemaName := newFlagSet("inspect", stderr)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	schemaInfo, err := lookupSchema(*schemaName)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	mappings, err := schemaInfo.Inspect()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tGRAPHQL TYPE\tGO\tGO TYPE")
+	for _, mapping := range mappings {
+		qlType := mapping.QLType
+		if qlType == "" {
+			qlType = "(not in schema)"
+		}
+		fmt.Fprintf(w, "%s.%s\t%s\t%s\t%s\n", mapping.TypeName, mapping.FieldName, qlType, mapping.Source, mapping.GoType)
+		for _, arg := range mapping.Args {
+			goType := arg.GoType
+			if goType == "" {
+				goType = "-"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t\t%s\n", arg.Name, arg.QLType, goType)
+		}
+	}
+	w.Flush()
+	return 0
+}
+
+func queryCmd(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags, schemaName := newFlagSet("query", stderr)
+	variablesJSON := flags.String("variables", "", "variables as JSON object, or @file to read them from a file")
+	operationName := flags.String("operation", "", "name of the operation to run if the document has more than one")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "query takes at most one file, - or none for stdin")
+		return 2
+	}
+
+	var query []byte
+	var err error
+	if file := flags.Arg(0); file == "" || file == "-" {
+		query, err = ioutil.ReadAll(stdin)
+	} else {
+		query, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	var variables map[string]interface{}
+	if *variablesJSON != "" {
+		data := []byte(*variablesJSON)
+		if strings.HasPrefix(*variablesJSON, "@") {
+			if data, err = ioutil.ReadFile(strings.TrimPrefix(*variablesJSON, "@")); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		if err := json.Unmarshal(data, &variables); err != nil {
+			fmt.Fprintf(stderr, "invalid variables: %v\n", err)
+			return 2
+		}
+	}
+
+	schema, ok := buildSchema(*schemaName, stderr)
+	if !ok {
+		return 1
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  string(query),
+		VariableValues: variables,
+		OperationName:  *operationName,
+		Context:        gographer.WithLoaders(gographer.WithVariables(context.Background(), variables)),
+	})
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	stdout.Write(append(out, '\n'))
+	if result.HasErrors() {
+		return 1
+	}
+	return 0
+}
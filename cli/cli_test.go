@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"github.com/xinhuang327/gographer"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type cliTestRoot struct{}
+
+type cliTestGreetArgs struct {
+	Name string `json:"name"`
+}
+
+func (r *cliTestRoot) GetGreeting(args cliTestGreetArgs) string {
+	return "hello " + args.Name
+}
+
+func (r *cliTestRoot) GetFailing() (string, error) {
+	return "", errors.New("failing")
+}
+
+func newCLITestSchema() *gographer.SchemaInfo {
+	sch := gographer.NewSchemaInfo()
+	sch.RegType(&cliTestRoot{}).SetRoot().ResolvedFields()
+	return sch
+}
+
+// Run with the test schema registered under the given names, returns the exit status, stdout and stderr.
+func runCLI(names []string, stdin string, args ...string) (int, string, string) {
+	schemas = nil
+	for _, name := range names {
+		RegisterSchema(name, newCLITestSchema)
+	}
+	var stdout, stderr bytes.Buffer
+	status := Run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return status, stdout.String(), stderr.String()
+}
+
+func TestQueryCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gographer-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	queryFile := filepath.Join(dir, "query.graphql")
+	ioutil.WriteFile(queryFile, []byte(`query A { greeting(name: "file") } query B { failing }`), 0644)
+	variablesFile := filepath.Join(dir, "variables.json")
+	ioutil.WriteFile(variablesFile, []byte(`{"name": "vars"}`), 0644)
+
+	tests := []struct {
+		name   string
+		names  []string
+		stdin  string
+		args   []string
+		status int
+		stdout string
+		stderr string // start of stderr
+	}{
+		{"stdin", []string{"test"}, `{ greeting(name: "stdin") }`, []string{"query"}, 0,
+			"{\n  \"data\": {\n    \"greeting\": \"hello stdin\"\n  }\n}\n", ""},
+		{"variables", []string{"test"}, `query($name: String) { greeting(name: $name) }`, []string{"query", "-variables", `{"name": "json"}`, "-"}, 0,
+			"{\n  \"data\": {\n    \"greeting\": \"hello json\"\n  }\n}\n", ""},
+		{"variables file", []string{"test"}, `query($name: String) { greeting(name: $name) }`, []string{"query", "-variables", "@" + variablesFile}, 0,
+			"{\n  \"data\": {\n    \"greeting\": \"hello vars\"\n  }\n}\n", ""},
+		{"file and operation", []string{"test"}, "", []string{"query", "-operation", "A", queryFile}, 0,
+			"{\n  \"data\": {\n    \"greeting\": \"hello file\"\n  }\n}\n", ""},
+		{"errors", []string{"test"}, "", []string{"query", "-operation", "B", queryFile}, 1,
+			"{\n  \"data\": {\n    \"failing\": null\n  },\n  \"errors\": [\n    {\n      \"message\": \"failing\",\n      \"locations\": [\n        {\n          \"line\": 1,\n          \"column\": 46\n        }\n      ],\n      \"path\": [\n        \"failing\"\n      ]\n    }\n  ]\n}\n", ""},
+		{"schema by name", []string{"a", "b"}, `{ greeting }`, []string{"query", "-schema", "b"}, 0,
+			"{\n  \"data\": {\n    \"greeting\": \"hello \"\n  }\n}\n", ""},
+		{"schema name needed", []string{"a", "b"}, `{ greeting }`, []string{"query"}, 1,
+			"", "more than one schema registered, use -schema with one of a, b\n"},
+		{"unknown schema", []string{"a"}, `{ greeting }`, []string{"query", "-schema", "c"}, 1,
+			"", "schema \"c\" is not registered, registered: a\n"},
+		{"no schema", nil, `{ greeting }`, []string{"query"}, 1,
+			"", "no schema registered, call cli.RegisterSchema in main\n"},
+		{"invalid variables", []string{"test"}, `{ greeting }`, []string{"query", "-variables", "{"}, 2,
+			"", "invalid variables: "},
+		{"missing file", []string{"test"}, "", []string{"query", filepath.Join(dir, "missing.graphql")}, 1,
+			"", "open "},
+		{"no command", []string{"test"}, "", nil, 2,
+			"", "usage: gographer <command> [flags]"},
+		{"unknown command", []string{"test"}, "", []string{"serve"}, 2,
+			"", "unknown command \"serve\"\nusage: gographer <command> [flags]"},
+	}
+	for _, test := range tests {
+		status, stdout, stderr := runCLI(test.names, test.stdin, test.args...)
+		if status != test.status || stdout != test.stdout || !strings.HasPrefix(stderr, test.stderr) || (test.stderr == "" && stderr != "") {
+			t.Errorf("%s: got status %d, stdout %q, stderr %q, want %d, %q, %q", test.name, status, stdout, stderr, test.status, test.stdout, test.stderr)
+		}
+	}
+}
+
+func TestSchemaCommand(t *testing.T) {
+	status, sdl, stderr := runCLI([]string{"test"}, "", "schema", "-format", "sdl")
+	if status != 0 || !strings.Contains(sdl, "type cliTestRoot {\n") || !strings.Contains(sdl, "  greeting(name: String): String\n") {
+		t.Errorf("got status %d, SDL %q, stderr %q", status, sdl, stderr)
+	}
+
+	status, introspection, stderr := runCLI([]string{"test"}, "", "schema")
+	if status != 0 || !strings.HasPrefix(introspection, "{\n  \"data\": {\n    \"__schema\": {") || !strings.Contains(introspection, `"name": "cliTestRoot"`) {
+		t.Errorf("got status %d, introspection %q, stderr %q", status, introspection, stderr)
+	}
+	if _, again, _ := runCLI([]string{"test"}, "", "schema"); again != introspection {
+		t.Error("introspection JSON isn't stable")
+	}
+
+	dir, err := ioutil.TempDir("", "gographer-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "schema.graphql")
+	if status, stdout, _ := runCLI([]string{"test"}, "", "schema", "-format", "sdl", "-o", output); status != 0 || stdout != "" {
+		t.Errorf("got status %d, stdout %q", status, stdout)
+	}
+	if written, _ := ioutil.ReadFile(output); string(written) != sdl {
+		t.Errorf("got file %q, want %q", written, sdl)
+	}
+
+	if status, _, stderr := runCLI([]string{"test"}, "", "schema", "-format", "xml"); status != 2 || stderr != "unknown format \"xml\", use json or sdl\n" {
+		t.Errorf("got status %d, stderr %q", status, stderr)
+	}
+}
+
+func TestInspectCommand(t *testing.T) {
+	status, stdout, stderr := runCLI([]string{"test"}, "", "inspect")
+	lines := strings.Split(stdout, "\n")
+	var fields []string
+	for _, line := range lines {
+		fields = append(fields, strings.Join(strings.Fields(line), " "))
+	}
+	want := []string{
+		"FIELD GRAPHQL TYPE GO GO TYPE",
+		"cliTestRoot.failing String method (*cliTestRoot).GetFailing func(*cli.cliTestRoot) (string, error)",
+		"cliTestRoot.greeting String method (*cliTestRoot).GetGreeting func(*cli.cliTestRoot, cli.cliTestGreetArgs) string",
+		"name String string",
+	}
+	if status != 0 || stderr != "" || !strings.HasPrefix(strings.Join(fields, "\n"), strings.Join(want, "\n")) {
+		t.Errorf("got status %d, stderr %q, stdout\n%s", status, stderr, stdout)
+	}
+}
@@ -16,6 +16,14 @@ type User struct {
 	ID string `json:"id"`
 }
 
+type TodoStatus string
+
+const (
+	TodoStatusAny        TodoStatus = "any"
+	TodoStatusCompleted  TodoStatus = "completed"
+	TodoStatusIncomplete TodoStatus = "incomplete"
+)
+
 // Mock data
 var viewer = &User{ViewerId}
 var usersById = map[string]*User{
@@ -50,21 +58,21 @@ func GetTodo(id string) *Todo {
 	return nil
 }
 
-func GetTodos(status string) []*Todo {
+func GetTodos(status TodoStatus) []*Todo {
 	todos := []*Todo{}
 	for _, todoId := range todoIdsByUser[ViewerId] {
 		if todo := GetTodo(todoId); todo != nil {
 
 			switch status {
-			case "completed":
+			case TodoStatusCompleted:
 				if todo.Complete {
 					todos = append(todos, todo)
 				}
-			case "incomplete":
+			case TodoStatusIncomplete:
 				if !todo.Complete {
 					todos = append(todos, todo)
 				}
-			case "any":
+			case TodoStatusAny:
 				fallthrough
 			default:
 				todos = append(todos, todo)
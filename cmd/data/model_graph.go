@@ -13,7 +13,7 @@ type AddTodoInput struct {
 }
 
 type AddTodoOutput struct {
-	TodoEdge relay.EdgeType //`elemType:"Todo"`
+	TodoEdge relay.EdgeType `elemType:"Todo"`
 	Viewer   *User
 }
 
@@ -31,7 +31,7 @@ func (m *Mutation) AddTodo(in AddTodoInput) *AddTodoOutput {
 }
 
 type ChangeTodoStatusInput struct {
-	Id       string `nonNull:"true"`
+	Id       string `gqlid:"Todo"`
 	Complete bool   `nonNull:"true"`
 }
 
@@ -41,10 +41,8 @@ type ChangeTodoStatusOutput struct {
 }
 
 func (m *Mutation) ChangeTodoStatus(in ChangeTodoStatusInput) *ChangeTodoStatusOutput {
-	resolvedId := relay.FromGlobalID(in.Id) // TODO: ID conversion could be handled outside the function
-	todoID := resolvedId.ID
-	ChangeTodoStatus(todoID, in.Complete)
-	return &ChangeTodoStatusOutput{GetTodo(todoID), GetViewer()}
+	ChangeTodoStatus(in.Id, in.Complete)
+	return &ChangeTodoStatusOutput{GetTodo(in.Id), GetViewer()}
 }
 
 type MarkAllTodosInput struct {
@@ -52,8 +50,8 @@ type MarkAllTodosInput struct {
 }
 
 type MarkAllTodosOutput struct {
-	ChangedTodosConnection []*Todo `json:"changedTodos"`
-	Viewer                 *User
+	ChangedTodos []*Todo
+	Viewer       *User
 }
 
 func (m *Mutation) MarkAllTodos(in MarkAllTodosInput) *MarkAllTodosOutput {
@@ -78,7 +76,7 @@ func (m *Mutation) RemoveCompletedTodos() *RemoveCompletedTodosOutput {
 }
 
 type RemoveTodoInput struct {
-	Id string `nonNull:"true"`
+	Id string `gqlid:"Todo"`
 }
 
 type RemoveTodoOutput struct {
@@ -87,21 +85,18 @@ type RemoveTodoOutput struct {
 }
 
 func (m *Mutation) RemoveTodo(in RemoveTodoInput) *RemoveTodoOutput {
-	resolvedId := relay.FromGlobalID(in.Id)
-	RemoveTodo(resolvedId.ID)
-	return &RemoveTodoOutput{relay.ToGlobalID(resolvedId.Type, resolvedId.ID), GetViewer()}
+	RemoveTodo(in.Id)
+	return &RemoveTodoOutput{relay.ToGlobalID("Todo", in.Id), GetViewer()}
 }
 
 type RenameTodoInput struct {
-	Id   string `nonNull:"true"`
+	Id   string `gqlid:"Todo"`
 	Text string `nonNull:"true"`
 }
 
 func (m *Mutation) RenameTodo(in RenameTodoInput) *ChangeTodoStatusOutput {
-	resolvedId := relay.FromGlobalID(in.Id)
-	todoID := resolvedId.ID
-	RenameTodo(todoID, in.Text)
-	return &ChangeTodoStatusOutput{GetTodo(todoID), GetViewer()}
+	RenameTodo(in.Id, in.Text)
+	return &ChangeTodoStatusOutput{GetTodo(in.Id), GetViewer()}
 }
 
 func (r *Root) GetViewer() *User {
@@ -110,10 +105,11 @@ func (r *Root) GetViewer() *User {
 
 // Struct arg's field name must be exported (Upper case first letter, will use lower case first letter in GraphQL)
 type GetTodosInput struct {
-	Status string `def:"any"`
+	Status TodoStatus `def:"any"`
 }
 
-func (u *User) GetTodos(p GetTodosInput) []*Todo {
+// Paged as TodoConnection with first/after/last/before arguments
+func (u *User) GetTodosConnection(p GetTodosInput) []*Todo {
 	return GetTodos(p.Status)
 }
 
@@ -2,14 +2,26 @@ package data
 
 import (
 	gg "github.com/xinhuang327/gographer"
+	"golang.org/x/net/context"
 )
 
 func GetModelSchemaInfo() *gg.SchemaInfo {
 	sch := gg.NewSchemaInfo()
 
+	sch.RegEnum(TodoStatus("")).
+		Value("any", TodoStatusAny, "All todos").
+		Value("completed", TodoStatusCompleted, "Completed todos only").
+		Value("incomplete", TodoStatusIncomplete, "Incomplete todos only")
+
 	sch.RegType(Todo{}).
-		SetIDResolver(func(id string) interface{} {
-			return GetTodo(id)
+		SetBatchIDResolver(func(ctx context.Context, ids []string) ([]interface{}, error) {
+			todos := make([]interface{}, len(ids))
+			for i, id := range ids {
+				if todo := GetTodo(id); todo != nil {
+					todos[i] = todo
+				}
+			}
+			return todos, nil
 		}).
 		IDField("id", nil).SimpleFields()
 
@@ -3,19 +3,27 @@ package gographer
 import (
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/relay"
+	"golang.org/x/net/context"
 	"reflect"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
-	"fmt"
-	"strconv"
-	"runtime"
-	"runtime/debug"
 )
 
-func getComplexQLType(
+var (
+	edgeType            = reflect.TypeOf(relay.EdgeType{})
+	relayConnectionType = reflect.TypeOf(relay.Connection{})
+)
+
+// GraphQL type of a field's Go type, slices of object types are Relay connections if asConnection is set.
+// Edges and connections built by the resolver (relay.EdgeType and relay.Connection) are of the registered type
+// elemTypeName, inferred from a <Type>Edge or <Type>Connection field name if empty.
+func (sch *SchemaInfo) getComplexQLType(
 	returnType reflect.Type,
 	fieldName string,
+	elemTypeName string,
+	asConnection bool,
 	qlTypes map[string]*graphql.Object,
 	qlConns map[string]*relay.GraphQLConnectionDefinitions) (graphql.Output, QLTypeKind) {
 
@@ -37,30 +45,20 @@ func getComplexQLType(
 
 	var elemQLType graphql.Output
 
-	var elemTypeName = elemType.Name()
 	isPrimitive := true
-	if elemQLType = ToQLType(elemType); elemQLType == nil {
+	if elemQLType = sch.toQLType(elemType); elemQLType == nil {
 		isPrimitive = false
-		if qlType, ok := qlTypes[elemTypeName]; ok {
-			elemQLType = qlType
+		if typ, ok := sch.typesByType[elemType]; ok && qlTypes[typ.Name] != nil {
+			elemQLType = qlTypes[typ.Name]
+		} else if qlType, ok := sch.qlAbstractTypes[elemType]; ok {
+			elemQLType = qlType // interface or union
 		}
 	}
 
-	inferredElemTypeName := inferTypeNameFromField(fieldName)
-
-	//
-	//if elemType == reflect.TypeOf(relay.EdgeType{}) {
-	//	if inferredElemTypeName != "" {
-	//		elemQLType := qlTypes[inferredElemTypeName]
-	//		conn := getOrCreateConnection(inferredElemTypeName, elemQLType, qlConns)
-	//		returnQLType = conn.EdgeType
-	//		qlTypeKind = QLTypeKind_Edge
-	//	} else {
-	//		Warning("Cannot infer type name for connection", fieldName, elemType)
-	//	}
-	//}
+	if elemTypeName == "" {
+		elemTypeName = inferTypeNameFromField(fieldName)
+	}
 
-	// TODO: Better handling Connection and Edge types...
 	if elemQLType != nil {
 		if !isList {
 			returnQLType = elemQLType
@@ -70,10 +68,9 @@ func getComplexQLType(
 				qlTypeKind = QLTypeKind_Struct
 			}
 		} else {
-			isConnection := false
-			if isConnection {
+			if nodeQLType, isObject := elemQLType.(*graphql.Object); asConnection && isObject {
 				// connection
-				conn := getOrCreateConnection(elemTypeName, elemQLType, qlConns)
+				conn := getOrCreateConnection(nodeQLType.Name(), nodeQLType, qlConns)
 				returnQLType = conn.ConnectionType
 				qlTypeKind = QLTypeKind_Connection
 			} else {
@@ -82,18 +79,23 @@ func getComplexQLType(
 				qlTypeKind = QLTypeKind_SimpleList
 			}
 		}
-	} else if elemType == reflect.TypeOf(relay.EdgeType{}) {
-		if inferredElemTypeName != "" {
-			elemQLType := qlTypes[inferredElemTypeName]
-			conn := getOrCreateConnection(inferredElemTypeName, elemQLType, qlConns)
-			returnQLType = conn.EdgeType
-			qlTypeKind = QLTypeKind_Edge
+	} else if elemType == edgeType || elemType == relayConnectionType {
+		if elemTypeName == "" {
+			sch.logger.Warn("Cannot infer node type, use elemType tag or ElemInterface", "field", fieldName, "elemType", elemType)
+		} else if nodeQLType, ok := qlTypes[elemTypeName]; ok {
+			conn := getOrCreateConnection(nodeQLType.Name(), nodeQLType, qlConns)
+			if elemType == edgeType {
+				returnQLType = conn.EdgeType
+				qlTypeKind = QLTypeKind_Edge
+			} else {
+				returnQLType = conn.ConnectionType
+				qlTypeKind = QLTypeKind_Connection
+			}
 		} else {
-			Warning("Cannot infer type name for connection", fieldName, elemType)
+			sch.logger.Warn("Node type is not registered", "field", fieldName, "nodeType", elemTypeName)
 		}
 	} else {
-		Warning("Cannot resolve QL type for return type", returnType, "elemType", elemType)
-		fmt.Println(qlTypes)
+		sch.logger.Warn("Cannot resolve QL type for return type", "field", fieldName, "returnType", returnType, "elemType", elemType)
 	}
 
 	return returnQLType, qlTypeKind
@@ -108,8 +110,9 @@ func getOrCreateConnection(
 
 	if conn, found = qlConns[elemTypeName]; !found {
 		conn = relay.ConnectionDefinitions(relay.ConnectionConfig{
-			Name:     elemTypeName,
-			NodeType: elemQLType.(*graphql.Object),
+			Name:             elemTypeName,
+			NodeType:         elemQLType.(*graphql.Object),
+			ConnectionFields: connectionFields(),
 		})
 		qlConns[elemTypeName] = conn
 	}
@@ -117,6 +120,25 @@ func getOrCreateConnection(
 	return conn
 }
 
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// Index of the first GraphQL argument of a method or extension func type.
+// In(0) is the receiver or source object, it may be followed by a context.Context parameter.
+func firstArgIndex(funcType reflect.Type) int {
+	if funcType.NumIn() > 1 && funcType.In(1) == contextType {
+		return 2
+	}
+	return 1
+}
+
+// Value to pass as context.Context parameter, never an invalid reflect.Value.
+func contextValue(ctx context.Context) reflect.Value {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return reflect.ValueOf(&ctx).Elem()
+}
+
 func toEmptyInterfaceSlice(slice interface{}) []interface{} {
 	s := reflect.ValueOf(slice)
 	if s.Kind() != reflect.Slice {
@@ -130,20 +152,29 @@ func toEmptyInterfaceSlice(slice interface{}) []interface{} {
 	return ret
 }
 
-func lowerFirst(s string) string {
+func upperFirst(s string) string {
 	if s == "" {
 		return ""
 	}
 	r, n := utf8.DecodeRuneInString(s)
-	return string(unicode.ToLower(r)) + s[n:]
+	return string(unicode.ToUpper(r)) + s[n:]
 }
 
-func upperFirst(s string) string {
-	if s == "" {
-		return ""
+// Registered type name of an explicit node type, given by name or as instance of the Go type.
+func (sch *SchemaInfo) nodeTypeName(elemTypeName string, elemInterface interface{}) string {
+	if elemTypeName != "" || elemInterface == nil {
+		return elemTypeName
 	}
-	r, n := utf8.DecodeRuneInString(s)
-	return string(unicode.ToUpper(r)) + s[n:]
+	elemType := reflect.TypeOf(elemInterface)
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	for _, typ := range sch.types {
+		if typ.Type == elemType {
+			return typ.Name
+		}
+	}
+	return elemType.Name()
 }
 
 func inferTypeNameFromField(fieldName string) string {
@@ -199,6 +230,51 @@ func ToQLType(typ reflect.Type) graphql.Output {
 	}
 }
 
+// ToQLType with the schema's registered scalars and enums.
+func (sch *SchemaInfo) toQLType(typ reflect.Type) graphql.Output {
+	if scalar, ok := sch.scalarsByType[typ]; ok {
+		return scalar.qlType()
+	}
+	if typ.Kind() == reflect.Ptr {
+		if scalar, ok := sch.scalarsByType[typ.Elem()]; ok {
+			return scalar.qlType() // nil pointer serializes to null
+		}
+	}
+	if enum, ok := sch.enumsByType[typ]; ok {
+		return enum.qlType()
+	}
+	if typ.Kind() == reflect.Slice {
+		if elemQLType := sch.toQLType(typ.Elem()); elemQLType != nil {
+			return graphql.NewList(elemQLType)
+		}
+		return nil
+	}
+	if isWideInt(typ.Kind()) {
+		return sch.wideIntQLType()
+	}
+	return ToQLType(typ)
+}
+
+// Parse a default value tag, enums accept the value name, scalars use their value parser.
+func (sch *SchemaInfo) parseDefaultValue(str string, typ reflect.Type) interface{} {
+	if valueField, ok := optionalValueField(typ); ok {
+		return sch.parseDefaultValue(str, valueField.Type)
+	}
+	if typ.Kind() == reflect.Ptr {
+		return sch.parseDefaultValue(str, typ.Elem())
+	}
+	if scalar, ok := sch.scalarsByType[typ]; ok {
+		if scalar.parseValue == nil {
+			return nil
+		}
+		return scalar.parseValue(str)
+	}
+	if enum, ok := sch.enumsByType[typ]; ok {
+		return enum.parseValue(str)
+	}
+	return ParseString(str, typ)
+}
+
 func ParseString(str string, typ reflect.Type) interface{} {
 	switch typ.Kind() {
 	case reflect.Float32:
@@ -240,12 +316,3 @@ func ParseString(str string, typ reflect.Type) interface{} {
 	}
 	return nil
 }
-
-func Warning(a ...interface{}) {
-	_, file, line, _ := runtime.Caller(1)
-	idx := strings.LastIndex(file, "/")
-	prefix := fmt.Sprint("[Gographer warning @", file[idx + 1:], ":", line, "]")
-	a = append([]interface{}{prefix}, a...)
-	fmt.Println(a...)
-	fmt.Printf("%s", debug.Stack())
-}
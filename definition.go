@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/relay"
+	"golang.org/x/net/context"
 	"reflect"
 	"strings"
 )
@@ -12,6 +13,10 @@ import (
 const (
 	TAG_DefaultValue = "def"
 	TAG_NonNull      = "nonNull"
+	TAG_GlobalID     = "gqlid"      // type name of the global ID, field is decoded with relay.FromGlobalID
+	TAG_Connection   = "connection" // "true" makes a slice output field a Relay connection
+	TAG_ElemType     = "elemType"   // registered node type of a relay.EdgeType or relay.Connection output field
+	TAG_GraphQL      = "graphql"    // field name and options, e.g. `graphql:"name,nonNull"`, the name takes precedence over the json tag
 )
 
 const (
@@ -27,28 +32,106 @@ type QLTypeKind string
 type SchemaInfo struct {
 	types            []*TypeInfo
 	typesByName      map[string]*TypeInfo
+	typesByType      map[reflect.Type]*TypeInfo
+	enums            []*EnumInfo
+	enumsByType      map[reflect.Type]*EnumInfo
+	scalarTypes      []reflect.Type // registration order of scalarsByType
+	scalarsByType    map[reflect.Type]*ScalarInfo
+	inputObjects     map[string]*inputObjectInfo // input objects of argument structs by name
+	interfaces       []*InterfaceInfo
+	interfacesByType map[reflect.Type]*InterfaceInfo
+	unions           []*UnionInfo
+	unionsByType     map[reflect.Type]*UnionInfo
+	directives       []*graphql.Directive            // besides the specified ones
+	qlAbstractTypes  map[reflect.Type]graphql.Output // interfaces and unions, set by GetSchema
+	nodesField       *graphql.Field                  // root field nodes, set by GetSchema
 	rootInstance     interface{}
 	mutationInstance interface{}
+	panicHandler     PanicHandler
+	logger           Logger
+	strict           bool
+	wideInts         IntMapping
+	naming           NamingStrategy
+	fieldCase        FieldCase
+	invalidFields    map[string]bool // set by GetSchema from validation result
 }
 
 func NewSchemaInfo() *SchemaInfo {
-	return &SchemaInfo{
-		typesByName: make(map[string]*TypeInfo),
+	sch := &SchemaInfo{
+		typesByName:      make(map[string]*TypeInfo),
+		typesByType:      make(map[reflect.Type]*TypeInfo),
+		enumsByType:      make(map[reflect.Type]*EnumInfo),
+		scalarsByType:    make(map[reflect.Type]*ScalarInfo),
+		inputObjects:     make(map[string]*inputObjectInfo),
+		interfacesByType: make(map[reflect.Type]*InterfaceInfo),
+		unionsByType:     make(map[reflect.Type]*UnionInfo),
+		logger:           NopLogger,
+		wideInts:         IntMapping_Int,
+		naming:           DefaultNaming,
+		fieldCase:        CamelCase,
 	}
+	sch.regBuiltinScalars()
+	return sch
+}
+
+// Set the handler for panics in resolver, extension and mutation methods, and in batch funcs of loaders.
+// Panics are always logged, without a handler clients get DefaultPanicMessage.
+func (sch *SchemaInfo) SetPanicHandler(handler PanicHandler) *SchemaInfo {
+	sch.panicHandler = handler
+	return sch
+}
+
+// Set the logger for build time and resolve time diagnostics, should be set before registering types.
+// Nil restores the silent NopLogger.
+func (sch *SchemaInfo) SetLogger(logger Logger) *SchemaInfo {
+	if logger == nil {
+		logger = NopLogger
+	}
+	sch.logger = logger
+	return sch
+}
+
+// In strict mode GetSchema refuses to build a schema with registration errors.
+// Otherwise invalid fields are left out of the schema and the errors are still returned.
+func (sch *SchemaInfo) SetStrict(strict bool) *SchemaInfo {
+	sch.strict = strict
+	return sch
+}
+
+// Set how int64, uint, uint32 and uint64 map to GraphQL, IntMapping_Int by default for compatibility.
+// Lossy Int mappings are reported as registration errors, the fields are still built unless in strict mode.
+func (sch *SchemaInfo) SetWideIntMapping(mapping IntMapping) *SchemaInfo {
+	sch.wideInts = mapping
+	return sch
+}
+
+// Register a directive besides @include, @skip and @deprecated, e.g. for tools reading the SDL.
+func (sch *SchemaInfo) RegDirective(directive *graphql.Directive) *SchemaInfo {
+	sch.directives = append(sch.directives, directive)
+	return sch
 }
 
 func (sch *SchemaInfo) RegType(instance interface{}) *TypeInfo {
 	typeDef := NewTypeInfo(instance)
+	typeDef.schema = sch
+	typeDef.Name = sch.typeName(typeDef.Type, TypeKind_Object)
 	sch.types = append(sch.types, typeDef)
-	sch.typesByName[typeDef.Name] = typeDef
+	sch.indexTypeName(typeDef.Name)
+	if _, exists := sch.typesByType[typeDef.Type]; !exists {
+		sch.typesByType[typeDef.Type] = typeDef
+	}
 	return typeDef
 }
 
 type TypeInfo struct {
 	Name           string
 	Type           reflect.Type
-	idResolver     IDResolver
+	idResolver     ContextIDResolver
+	idLoader       *Loader // batch ID resolver
+	idFieldName    string  // field added by IDField, rebuilt by SetName
+	idFetcher      relay.GlobalIDFetcherFn
 	fields         graphql.Fields
+	simpleFields   []simpleFieldInfo // GraphQL type resolved when building, see SchemaInfo.toQLType
 	resolvedFields []ResolvedFieldInfo
 	mutationFields []MutationFieldInfo
 	isRootType     bool
@@ -56,10 +139,19 @@ type TypeInfo struct {
 	instance       interface{}
 	isNonNode      bool
 	embeddedTypes  map[string]reflect.Type
+	schema         *SchemaInfo // set by RegType
+	errors         SchemaErrors
 }
 
 type IDResolver func(id string) interface{}
 
+// ContextIDResolver resolves the object of an ID with the request context, nil if there is none.
+// Errors are reported for the node field, e.g. for forbidden objects or failing lookups.
+type ContextIDResolver func(ctx context.Context, id string) (interface{}, error)
+
+// BatchIDResolver resolves the objects of many IDs at once, in the order of the IDs, nil for unknown IDs.
+type BatchIDResolver func(ctx context.Context, ids []string) ([]interface{}, error)
+
 func NewTypeInfo(instance interface{}) *TypeInfo {
 	type_ := reflect.TypeOf(instance)
 	if type_.Kind() == reflect.Ptr {
@@ -75,16 +167,217 @@ func NewTypeInfo(instance interface{}) *TypeInfo {
 	return &typeDef
 }
 
+func (typ *TypeInfo) logger() Logger {
+	if typ.schema == nil {
+		return NopLogger
+	}
+	return typ.schema.logger
+}
+
+// Schema naming the fields of the type, one with the default field case if the type isn't registered.
+func (typ *TypeInfo) namingSchema() *SchemaInfo {
+	if typ.schema == nil {
+		return &SchemaInfo{}
+	}
+	return typ.schema
+}
+
+// GraphQL name of a struct field, see SchemaInfo.fieldName.
+func (typ *TypeInfo) fieldName(field reflect.StructField) (string, bool) {
+	return typ.namingSchema().fieldName(field)
+}
+
+// Record a registration problem, reported by Validate with the type's name at that time, see SetName.
+func (typ *TypeInfo) addError(fieldName string, format string, a ...interface{}) {
+	typ.errors = append(typ.errors, &SchemaError{FieldName: fieldName, Message: fmt.Sprintf(format, a...)})
+}
+
+// Record a later definition of a field, it's left out and the first definition stays.
+func (typ *TypeInfo) addDuplicateError(fieldName string) {
+	typ.errors = append(typ.errors, &SchemaError{FieldName: fieldName, Message: "field is defined more than once", keepField: true})
+}
+
+// Pointer to the value of the type in a resolved source, the source itself, a value of the type
+// or a struct embedding it. Invalid if the source has none.
+func (typ *TypeInfo) sourceValue(source interface{}) reflect.Value {
+	return findTypeValue(reflect.ValueOf(source), typ.Type)
+}
+
+func findTypeValue(v reflect.Value, type_ reflect.Type) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		if v.Type() == reflect.PtrTo(type_) {
+			return v
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return v
+	}
+	if v.Type() == type_ {
+		if v.CanAddr() {
+			return v.Addr()
+		}
+		ptr := reflect.New(type_) // copy, so methods with pointer receiver can be called
+		ptr.Elem().Set(v)
+		return ptr
+	}
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.Anonymous && field.PkgPath == "" {
+				if found := findTypeValue(v.Field(i), type_); found.IsValid() {
+					return found
+				}
+			}
+		}
+	}
+	return reflect.Value{}
+}
+
+// Find method for pointer type first, then value type.
+func (typ *TypeInfo) findMethod(methodName string) (reflect.Method, bool) {
+	if method, found := reflect.PtrTo(typ.Type).MethodByName(methodName); found {
+		return method, true
+	}
+	return typ.Type.MethodByName(methodName)
+}
+
+// Function type of a resolved field's method or extension func.
+func (typ *TypeInfo) resolvedFuncType(rf ResolvedFieldInfo) (reflect.Type, bool) {
+	if rf.ExtensionFunc != nil {
+		funcType := reflect.TypeOf(rf.ExtensionFunc)
+		return funcType, funcType.Kind() == reflect.Func
+	}
+	if method, found := typ.findMethod(rf.MethodName); found {
+		return method.Func.Type(), true
+	}
+	return nil, false
+}
+
+// Go type to build the resolved field's GraphQL type from, a Page or Pager result is a slice of ElemInterface's type,
+// a deferred func() T result is T, a thunk from Loader.Load is ElemInterface's type.
+func (rf ResolvedFieldInfo) returnGoType(funcType reflect.Type) reflect.Type {
+	returnType := funcType.Out(0)
+	if rf.ManualGoType != nil {
+		returnType = rf.ManualGoType
+	}
+	if returnType == thunkType && rf.ElemInterface != nil {
+		return reflect.TypeOf(rf.ElemInterface)
+	}
+	if resultType := deferredResultType(returnType); resultType != nil {
+		returnType = resultType
+	}
+	if isPageType(returnType) && rf.ElemInterface != nil {
+		elemType := reflect.TypeOf(rf.ElemInterface)
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+		returnType = reflect.SliceOf(elemType)
+	}
+	return returnType
+}
+
+// Whether a field with the name is defined explicitly, fields added automatically by SimpleFields yield to it.
+func (typ *TypeInfo) hasExplicitField(name string) bool {
+	if _, ok := typ.fields[name]; ok {
+		return true
+	}
+	for _, sf := range typ.simpleFields {
+		if sf.Name == name && !sf.auto {
+			return true
+		}
+	}
+	for _, rf := range typ.resolvedFields {
+		if rf.Name == name && !rf.autoSimple {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether a simple or resolved field with the name is already defined.
+func (typ *TypeInfo) hasField(name string) bool {
+	if _, ok := typ.fields[name]; ok {
+		return true
+	}
+	for _, sf := range typ.simpleFields {
+		if sf.Name == name {
+			return true
+		}
+	}
+	for _, rf := range typ.resolvedFields {
+		if rf.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Set the GraphQL name instead of the one given by the naming strategy, e.g. to rename a Go type in the schema.
+func (typ *TypeInfo) SetName(name string) *TypeInfo {
+	oldName := typ.Name
+	typ.Name = name
+	if sch := typ.schema; sch != nil {
+		sch.indexTypeName(oldName)
+		sch.indexTypeName(name)
+	}
+	if typ.idFieldName != "" {
+		typ.fields[typ.idFieldName] = relay.GlobalIDField(name, typ.idFetcher) // IDs carry the type name
+	}
+	return typ
+}
+
+// Types implement the Node interface if they have an ID resolver, unless set as non-node.
 func (typ *TypeInfo) SetNonNode() *TypeInfo {
 	typ.isNonNode = true
 	return typ
 }
 
+// Whether the type implements the Node interface and its IDs are resolved by node and nodes.
+func (typ *TypeInfo) isNode() bool {
+	return typ.hasIDResolver() && typ.hasNodeIDField()
+}
+
+// Whether the type has an ID resolver and is not set as non-node, root or mutation type.
+func (typ *TypeInfo) hasIDResolver() bool {
+	return !typ.isRootType && !typ.isMutationType && !typ.isNonNode && (typ.idResolver != nil || typ.idLoader != nil)
+}
+
+// Whether the type has the id field of type ID! the Node interface needs, e.g. added by IDField.
+func (typ *TypeInfo) hasNodeIDField() bool {
+	field, ok := typ.fields["id"]
+	if !ok {
+		return false
+	}
+	nonNull, ok := field.Type.(*graphql.NonNull)
+	return ok && nonNull.OfType == graphql.ID
+}
+
 func (typ *TypeInfo) SetIDResolver(f IDResolver) *TypeInfo {
+	if f == nil {
+		typ.idResolver = nil
+		return typ
+	}
+	return typ.SetContextIDResolver(func(ctx context.Context, id string) (interface{}, error) {
+		return f(id), nil
+	})
+}
+
+// Resolve node IDs with the request context, resolver errors are reported for the node field.
+func (typ *TypeInfo) SetContextIDResolver(f ContextIDResolver) *TypeInfo {
 	typ.idResolver = f
 	return typ
 }
 
+// Resolve node IDs in batches, IDs of one request are collected and resolved with as few calls as
+// possible and cached for the request, see Loader. Used instead of the IDResolver.
+func (typ *TypeInfo) SetBatchIDResolver(f BatchIDResolver) *TypeInfo {
+	typ.idLoader = NewLoader(BatchFunc(f))
+	return typ
+}
+
 func (typ *TypeInfo) SetEmbeddedTypes(ifaces ...interface{}) *TypeInfo {
 	for _, iface := range ifaces {
 		t := reflect.TypeOf(iface)
@@ -98,29 +391,61 @@ func (typ *TypeInfo) SetRoot() *TypeInfo {
 	return typ
 }
 
+// Add the struct field given by its Go or GraphQL name, the field is named like SimpleFields does.
 func (typ *TypeInfo) SimpleField(name string) *TypeInfo {
-	for i := 0; i < typ.Type.NumField(); i++ {
-		field := typ.Type.Field(i)
-		if field.Name == name || field.Tag.Get("json") == name {
-			if qlType := ToQLType(field.Type); qlType != nil {
-				return typ.AddField(name, &graphql.Field{
-					Type: qlType,
-				})
-			}
-		}
+	field, fieldName, found := typ.findStructField(name)
+	switch {
+	case !found:
+		typ.addError(name, "SimpleField not found")
+	case fieldName == "":
+		typ.addError(name, "SimpleField is unexported or excluded by its tag")
+	default:
+		typ.addSimpleField(fieldName, field)
 	}
-	Warning("SimpleField not found", typ.Name, name)
 	return typ
 }
 
+// Add the global ID field for the struct field given by its Go or GraphQL name, the field is named like SimpleFields does.
 func (typ *TypeInfo) IDField(name string, idFetcher relay.GlobalIDFetcherFn) *TypeInfo {
+	_, fieldName, found := typ.findStructField(name)
+	switch {
+	case !found:
+		typ.addError(name, "IDField not found")
+	case fieldName == "":
+		typ.addError(name, "IDField is unexported or excluded by its tag")
+	case typ.hasExplicitField(fieldName):
+		typ.addDuplicateError(fieldName)
+	default:
+		typ.AddField(fieldName, relay.GlobalIDField(typ.Name, idFetcher))
+		typ.idFieldName, typ.idFetcher = fieldName, idFetcher
+	}
+	return typ
+}
+
+// Struct field with the Go or GraphQL name and its GraphQL name, empty if the field is left out of the schema.
+func (typ *TypeInfo) findStructField(name string) (reflect.StructField, string, bool) {
 	for i := 0; i < typ.Type.NumField(); i++ {
 		field := typ.Type.Field(i)
-		if field.Name == name || field.Tag.Get("json") == name {
-			return typ.AddField(name, relay.GlobalIDField(typ.Name, idFetcher))
+		if fieldName, ok := typ.fieldName(field); ok && fieldName == name {
+			return field, fieldName, true
 		}
 	}
-	Warning("IDField not found", typ.Name, name)
+	for i := 0; i < typ.Type.NumField(); i++ {
+		field := typ.Type.Field(i)
+		if field.Name == name {
+			fieldName, _ := typ.fieldName(field)
+			return field, fieldName, true
+		}
+	}
+	return reflect.StructField{}, "", false
+}
+
+func (typ *TypeInfo) addSimpleField(name string, field reflect.StructField) *TypeInfo {
+	if typ.hasExplicitField(name) {
+		typ.addDuplicateError(name)
+		return typ
+	}
+	typ.simpleFields = append(typ.simpleFields, simpleFieldInfo{Name: name, GoType: field.Type, goName: field.Name})
 	return typ
 }
 
@@ -135,8 +460,8 @@ func (typ *TypeInfo) SimpleFields() *TypeInfo {
 }
 
 // Recursively process all the fields, including embedded struct fields.
+// Fields are included when building if SchemaInfo.toQLType resolves their type, i.e. primitives, enums and scalars.
 func (typ *TypeInfo) processSimpleFields(nestFieldsInput []string, nestTypeInput reflect.Type) {
-	//fmt.Println("processSimpleFields", nestTypeInput, nestFieldsInput)
 	var nestFields []string
 	for _, nf := range nestFieldsInput {
 		nestFields = append(nestFields, nf)
@@ -145,88 +470,61 @@ func (typ *TypeInfo) processSimpleFields(nestFieldsInput []string, nestTypeInput
 
 	for i := 0; i < nestType.NumField(); i++ {
 		field := nestType.Field(i)
-		fmt.Println(nestType.Name(), i, field.Name, field.Type)
+		typ.logger().Debug("Processing simple field", "type", typ.Name, "struct", nestType.Name(), "field", field.Name, "fieldType", field.Type)
 		var fullFieldName = field.Name
-		var fieldName string
-		if jsonTag := field.Tag.Get("json"); jsonTag != "" {
-			fieldName = jsonTag
-		} else {
-			fieldName = field.Name
+		// handle embedded struct
+		if field.Type.Kind() == reflect.Struct && field.Name == field.Type.Name() && field.Type == typ.embeddedTypes[field.Name] {
+			var nextNestFields []string
+			for _, nf := range nestFields {
+				nextNestFields = append(nextNestFields, nf)
+			}
+			nextNestFields = append(nextNestFields, field.Name)
+			//nestType = field.Type will set previous call's nestType, don't do it.
+			typ.processSimpleFields(nextNestFields, field.Type)
+			continue
 		}
 
-		hasQLType := false
-		var qlType graphql.Output
-		if qlType = ToQLType(field.Type); qlType != nil {
-			if len(nestFields) == 0 {
-				typ.AddField(fieldName, &graphql.Field{
-					Type: qlType,
-				})
-			} else {
-				typ.resolvedFields = append(typ.resolvedFields, ResolvedFieldInfo{
-					Name:       fieldName,
-					Args:       nil,
-					AutoArgs:   true,
-					ManualType: qlType,
-					ExtensionFunc: func(s interface{}) interface{} {
-						val := reflect.ValueOf(s)
-						// iterate field value chain
-						for _, nf := range nestFields {
-							val = val.FieldByName(nf)
-						}
-						fieldValue := val.FieldByName(fullFieldName)
-						if fieldValue.IsValid() {
-							return fieldValue.Interface()
-						}
-						return nil
-					},
-				})
-			}
-			hasQLType = true
+		fieldName, ok := typ.fieldName(field)
+		if !ok {
+			continue // unexported or excluded by its tag
 		}
 
-		if !hasQLType {
-			// deal with struct field...
-			if field.Type.Kind() == reflect.Struct {
-				fmt.Println("Struct Field: ", typ.Name, field.Name, field.Type.Name())
-
-				if field.Type.Implements(TextMarshalerType) {
-					// time.Time
-					//fmt.Println("is TextMarshalerType")
-					fullFieldName := field.Name
-					typ.ExtensionField(fieldName, func(s interface{}) string {
-						val := reflect.ValueOf(s)
-						// iterate field value chain
-						for _, nf := range nestFields {
-							val = val.FieldByName(nf)
-						}
-						fieldValue := val.FieldByName(fullFieldName)
-						if fieldValue.IsValid() {
-							if textMarshaler, ok := fieldValue.Interface().(encoding.TextMarshaler); ok {
-								text, _ := textMarshaler.MarshalText()
-								return string(text)
-							}
-						}
-						return ""
-					}, AutoArgs)
-
-				} else {
-					// handle embedded struct
-					if field.Name == field.Type.Name() && field.Type == typ.embeddedTypes[field.Name] {
-						var nextNestFields []string
-						for _, nf := range nestFields {
-							nextNestFields = append(nextNestFields, nf)
-						}
-						nextNestFields = append(nextNestFields, field.Name)
-						//nestType = field.Type will set previous call's nestType, don't do it.
-						typ.processSimpleFields(nextNestFields, field.Type)
+		if typ.hasField(fieldName) {
+			continue // explicitly defined fields take precedence, e.g. IDField
+		}
+
+		if len(nestFields) == 0 {
+			typ.simpleFields = append(typ.simpleFields, simpleFieldInfo{Name: fieldName, GoType: field.Type, goName: field.Name, auto: true})
+		} else {
+			typ.resolvedFields = append(typ.resolvedFields, ResolvedFieldInfo{
+				Name:         fieldName,
+				Args:         nil,
+				AutoArgs:     true,
+				ManualGoType: field.Type,
+				autoSimple:   true,
+				ExtensionFunc: func(s interface{}) interface{} {
+					val := reflect.Indirect(reflect.ValueOf(s))
+					// iterate field value chain
+					for _, nf := range nestFields {
+						val = val.FieldByName(nf)
 					}
-				}
-			}
+					fieldValue := val.FieldByName(fullFieldName)
+					if fieldValue.IsValid() {
+						return fieldValue.Interface()
+					}
+					return nil
+				},
+			})
 		}
 	}
 }
 
 func (typ *TypeInfo) ResolvedField(name string, methodName string, args []ArgInfo) *TypeInfo {
+	typ.removeAutoResolvedField(name) // explicit fields replace the ones added by ResolvedFields
+	return typ.addResolvedField(name, methodName, args)
+}
+
+func (typ *TypeInfo) addResolvedField(name string, methodName string, args []ArgInfo) *TypeInfo {
 	autoArgs := IsAutoArgs(args)
 	if autoArgs {
 		args = nil
@@ -240,6 +538,36 @@ func (typ *TypeInfo) ResolvedField(name string, methodName string, args []ArgInf
 	return typ
 }
 
+// Add a resolved field returning a Relay connection of the method's slice result,
+// with first/after/last/before arguments besides the method's own.
+func (typ *TypeInfo) ConnectionField(name string, methodName string, args []ArgInfo) *TypeInfo {
+	typ.ResolvedField(name, methodName, args)
+	typ.resolvedFields[len(typ.resolvedFields)-1].IsConnection = true
+	return typ
+}
+
+// Add a connection field whose method returns a Page, Pager or relay.Connection of elemInstance's type,
+// the method may receive the connection arguments as trailing PageArgs parameter.
+func (typ *TypeInfo) PagerField(name string, methodName string, elemInstance interface{}, args []ArgInfo) *TypeInfo {
+	typ.ConnectionField(name, methodName, args)
+	typ.resolvedFields[len(typ.resolvedFields)-1].ElemInterface = elemInstance
+	return typ
+}
+
+// Add a resolved field whose method returns a relay.EdgeType of elemInstance's type.
+func (typ *TypeInfo) EdgeField(name string, methodName string, elemInstance interface{}, args []ArgInfo) *TypeInfo {
+	typ.ResolvedField(name, methodName, args)
+	typ.resolvedFields[len(typ.resolvedFields)-1].ElemInterface = elemInstance
+	return typ
+}
+
+// Add a resolved field whose method returns a thunk of elemInstance's type, e.g. from Loader.Load.
+func (typ *TypeInfo) LoaderField(name string, methodName string, elemInstance interface{}, args []ArgInfo) *TypeInfo {
+	typ.ResolvedField(name, methodName, args)
+	typ.resolvedFields[len(typ.resolvedFields)-1].ElemInterface = elemInstance
+	return typ
+}
+
 func (typ *TypeInfo) ExtensionField(name string, extensionFunc interface{}, args []ArgInfo) *TypeInfo {
 	autoArgs := IsAutoArgs(args)
 	if autoArgs {
@@ -254,20 +582,63 @@ func (typ *TypeInfo) ExtensionField(name string, extensionFunc interface{}, args
 	return typ
 }
 
-// Auto adds resolved fields
+// Auto adds resolved fields, GetXxxConnection methods add connection field xxx.
+// Methods whose results need the node type, a Page, Pager or thunk of interface{}, are left to
+// PagerField and LoaderField. Fields defined explicitly, before or after, take precedence.
 func (typ *TypeInfo) ResolvedFields() *TypeInfo {
 	ptrType := reflect.PtrTo(typ.Type)
 	for i := 0; i < ptrType.NumMethod(); i++ {
 		method := ptrType.Method(i)
 		var methodName = method.Name
 		if strings.HasPrefix(methodName, "Get") {
-			fieldName := lowerFirst(strings.TrimPrefix(methodName, "Get"))
-			typ.ResolvedField(fieldName, methodName, AutoArgs)
+			if method.Type.NumOut() > 0 && needsNodeType(method.Type.Out(0)) {
+				typ.logger().Debug("Skipping method without node type", "type", typ.Name, "method", methodName)
+				continue
+			}
+			fieldName, isConnection := typ.namingSchema().methodFieldName(methodName)
+			if typ.hasExplicitResolvedField(fieldName) {
+				continue
+			}
+			typ.addResolvedField(fieldName, methodName, AutoArgs)
+			rf := &typ.resolvedFields[len(typ.resolvedFields)-1]
+			rf.IsConnection, rf.autoResolved = isConnection, true
 		}
 	}
 	return typ
 }
 
+// Whether a method result needs the node type given with PagerField or LoaderField.
+func needsNodeType(resultType reflect.Type) bool {
+	if resultType == thunkType {
+		return true
+	}
+	if deferred := deferredResultType(resultType); deferred != nil {
+		resultType = deferred
+	}
+	return isPageType(resultType)
+}
+
+func (typ *TypeInfo) hasExplicitResolvedField(name string) bool {
+	for _, rf := range typ.resolvedFields {
+		if rf.Name == name && !rf.autoResolved && !rf.autoSimple {
+			return true
+		}
+	}
+	return false
+}
+
+func (typ *TypeInfo) removeAutoResolvedField(name string) {
+	resolvedFields := typ.resolvedFields[:0]
+	for _, rf := range typ.resolvedFields {
+		if rf.Name != name || !rf.autoResolved {
+			resolvedFields = append(resolvedFields, rf)
+		}
+	}
+	typ.resolvedFields = resolvedFields
+}
+
+// Argument of a method taking plain parameters, typed from the Go parameter.
+// Plain args can't be global IDs, use AutoArgs with a TAG_GlobalID field for those.
 type ArgInfo struct {
 	Name         string
 	DefaultValue interface{}
@@ -281,6 +652,10 @@ func IsAutoArgs(args []ArgInfo) bool {
 }
 
 func (typ *TypeInfo) AddField(name string, field *graphql.Field) *TypeInfo {
+	if typ.hasExplicitField(name) {
+		typ.addDuplicateError(name)
+		return typ
+	}
 	typ.fields[name] = field
 	return typ
 }
@@ -292,6 +667,18 @@ type ResolvedFieldInfo struct {
 	AutoArgs      bool
 	ExtensionFunc interface{}
 	ManualType    graphql.Output
+	ManualGoType  reflect.Type // used instead of the function's return type to resolve the GraphQL type
+	IsConnection  bool         // slice result is paged as Relay connection
+	ElemInterface interface{}  // node type of a Page, Pager, relay.Connection, relay.EdgeType or thunk result
+	autoSimple    bool         // embedded struct's field added by SimpleFields
+	autoResolved  bool         // added by ResolvedFields, replaced by an explicit definition
+}
+
+type simpleFieldInfo struct {
+	Name   string
+	GoType reflect.Type
+	goName string
+	auto   bool // added by SimpleFields, only included if the type resolves
 }
 
 func (typ *TypeInfo) SetMutation() *TypeInfo {
@@ -325,8 +712,7 @@ func (typ *TypeInfo) MutationFields() *TypeInfo {
 	for i := 0; i < ptrType.NumMethod(); i++ {
 		method := ptrType.Method(i)
 		var methodName = method.Name
-		var qlMutationName = lowerFirst(methodName)
-		typ.MutationField(qlMutationName, methodName, AutoArgs, AutoOutputs)
+		typ.MutationField(typ.namingSchema().caseName(methodName), methodName, AutoArgs, AutoOutputs)
 	}
 	return typ
 }
@@ -342,18 +728,10 @@ type MutationFieldInfo struct {
 
 type OutputInfo struct {
 	Name          string
-	ElemInterface interface{}
-	ElemTypeName  string
-}
-
-func (outputInfo OutputInfo) GetElementTypeName() string {
-	if outputInfo.ElemTypeName != "" {
-		return outputInfo.ElemTypeName
-	} else if outputInfo.ElemInterface != nil {
-		return reflect.TypeOf(outputInfo.ElemInterface).Name()
-	} else {
-		return ""
-	}
+	ElemInterface interface{} // node type of an edge or connection output, e.g. &Todo{}
+	ElemTypeName  string      // node type by its GraphQL name, used instead of ElemInterface
+	IsConnection  bool
+	goIndex       int // index of the field in the output struct of AutoOutputs
 }
 
 var AutoOutputs = []OutputInfo{OutputInfo{Name: "__AutoOutputs__"}}
@@ -0,0 +1,120 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"reflect"
+)
+
+// EnumInfo maps a Go named type, e.g. `type TodoStatus string` or an int based iota type, to a GraphQL enum.
+type EnumInfo struct {
+	Name        string
+	Type        reflect.Type
+	Description string
+	values      []EnumValueInfo
+	errors      SchemaErrors
+	qlEnum      *graphql.Enum // built lazily
+}
+
+type EnumValueInfo struct {
+	Name              string
+	Value             interface{}
+	Description       string
+	DeprecationReason string
+}
+
+func (sch *SchemaInfo) RegEnum(instance interface{}) *EnumInfo {
+	enum := NewEnumInfo(instance)
+	enum.Name = sch.typeName(enum.Type, TypeKind_Enum)
+	sch.enums = append(sch.enums, enum)
+	sch.enumsByType[enum.Type] = enum
+	return enum
+}
+
+func NewEnumInfo(instance interface{}) *EnumInfo {
+	type_ := reflect.TypeOf(instance)
+	return &EnumInfo{
+		Name: type_.Name(),
+		Type: type_,
+	}
+}
+
+func (enum *EnumInfo) SetDescription(description string) *EnumInfo {
+	enum.Description = description
+	enum.qlEnum = nil
+	return enum
+}
+
+// Add an enum value, the Go value is converted to the enum's Go type.
+func (enum *EnumInfo) Value(name string, value interface{}, description string) *EnumInfo {
+	val := reflect.ValueOf(value)
+	if !val.IsValid() || !val.Type().ConvertibleTo(enum.Type) || val.Kind() != enum.Type.Kind() {
+		enum.errors = append(enum.errors, &SchemaError{
+			FieldName: name,
+			Message:   fmt.Sprintf("enum value %#v cannot be converted to %v", value, enum.Type),
+		})
+		return enum
+	}
+	enum.values = append(enum.values, EnumValueInfo{
+		Name:        name,
+		Value:       val.Convert(enum.Type).Interface(),
+		Description: description,
+	})
+	enum.qlEnum = nil
+	return enum
+}
+
+// Mark a previously added value as deprecated.
+func (enum *EnumInfo) Deprecate(name string, reason string) *EnumInfo {
+	for i := range enum.values {
+		if enum.values[i].Name == name {
+			enum.values[i].DeprecationReason = reason
+			enum.qlEnum = nil
+			return enum
+		}
+	}
+	enum.errors = append(enum.errors, &SchemaError{FieldName: name, Message: "cannot deprecate unknown enum value"})
+	return enum
+}
+
+// Look up the Go value by enum value name, or by the Go value's string form, used for default values.
+func (enum *EnumInfo) parseValue(str string) interface{} {
+	for _, v := range enum.values {
+		if v.Name == str {
+			return v.Value
+		}
+	}
+	for _, v := range enum.values {
+		if fmt.Sprint(v.Value) == str {
+			return v.Value
+		}
+	}
+	return nil
+}
+
+func (enum *EnumInfo) qlType() *graphql.Enum {
+	if enum.qlEnum == nil {
+		values := make(graphql.EnumValueConfigMap)
+		for _, v := range enum.values {
+			values[v.Name] = &graphql.EnumValueConfig{
+				Value:             v.Value,
+				Description:       v.Description,
+				DeprecationReason: v.DeprecationReason,
+			}
+		}
+		enum.qlEnum = graphql.NewEnum(graphql.EnumConfig{
+			Name:        enum.Name,
+			Description: enum.Description,
+			Values:      values,
+		})
+	}
+	return enum.qlEnum
+}
+
+func (enum *EnumInfo) validate() SchemaErrors {
+	errs := enum.errors.withTypeName(enum.Name)
+	if len(enum.values) == 0 {
+		errs = append(errs, &SchemaError{TypeName: enum.Name, Message: "enum has no values"})
+	}
+	return errs
+}
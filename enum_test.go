@@ -0,0 +1,131 @@
+package gographer
+
+import (
+	"github.com/graphql-go/graphql"
+	"reflect"
+	"testing"
+)
+
+type enumTestStatus string
+
+const (
+	enumTestStatus_Open   enumTestStatus = "open"
+	enumTestStatus_Closed enumTestStatus = "closed"
+)
+
+type enumTestPriority int
+
+const (
+	enumTestPriority_Low enumTestPriority = iota
+	enumTestPriority_High
+)
+
+type enumTestTask struct {
+	Name     string           `json:"name"`
+	Status   enumTestStatus   `json:"status"`
+	Priority enumTestPriority `json:"priority"`
+}
+
+type enumTestRoot struct{}
+
+type enumTestTasksArgs struct {
+	Statuses []enumTestStatus `json:"statuses"`
+	Priority enumTestPriority `json:"priority" def:"LOW"`
+}
+
+func (r *enumTestRoot) GetTasks(args enumTestTasksArgs) []*enumTestTask {
+	var tasks []*enumTestTask
+	for _, status := range args.Statuses {
+		tasks = append(tasks, &enumTestTask{Name: string(status), Status: status, Priority: args.Priority})
+	}
+	return tasks
+}
+
+func (r *enumTestRoot) GetTop() enumTestPriority {
+	return enumTestPriority_High
+}
+
+type enumTestMutation struct{}
+
+type enumTestCloseInput struct {
+	Priority enumTestPriority `json:"priority"`
+}
+
+type enumTestClosePayload struct {
+	Status   enumTestStatus   `json:"status"`
+	Priority enumTestPriority `json:"priority"`
+}
+
+func (m *enumTestMutation) Close(in enumTestCloseInput) (*enumTestClosePayload, error) {
+	return &enumTestClosePayload{Status: enumTestStatus_Closed, Priority: in.Priority}, nil
+}
+
+func TestEnums(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegEnum(enumTestStatus("")).
+		Value("OPEN", enumTestStatus_Open, "not done yet").
+		Value("CLOSED", "closed", "done").
+		Deprecate("CLOSED", "use OPEN")
+	sch.RegEnum(enumTestPriority(0)).
+		Value("LOW", enumTestPriority_Low, "").
+		Value("HIGH", 1, "")
+	sch.RegType(&enumTestTask{}).SetNonNode().SimpleFields()
+	sch.RegType(&enumTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&enumTestMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{`{ top tasks(statuses: [OPEN, CLOSED]) { name status priority } }`, nil,
+			`{"data":{"tasks":[{"name":"open","priority":"LOW","status":"OPEN"},{"name":"closed","priority":"LOW","status":"CLOSED"}],"top":"HIGH"}}`},
+		{`query($s: [enumTestStatus], $p: enumTestPriority) { tasks(statuses: $s, priority: $p) { status priority } }`, map[string]interface{}{"s": []interface{}{"CLOSED"}, "p": "HIGH"},
+			`{"data":{"tasks":[{"priority":"HIGH","status":"CLOSED"}]}}`},
+		{`mutation { close(input: {priority: HIGH}) { status priority } }`, nil,
+			`{"data":{"close":{"priority":"HIGH","status":"CLOSED"}}}`},
+		{`{ tasks(statuses: [DONE]) { name } }`, nil,
+			`{"data":null,"errors":[{"message":"Argument \"statuses\" has invalid value [DONE].\nIn element #1: Expected type \"enumTestStatus\", found DONE.","locations":[{"line":1,"column":19}]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, test.variables); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+	// graphql-go doesn't keep the order of enum values
+	values := schema.Type("enumTestStatus").(*graphql.Enum).Values()
+	if len(values) != 2 {
+		t.Errorf("got %d enum values, want 2", len(values))
+	}
+	for _, value := range values {
+		got := []string{value.Description, value.DeprecationReason}
+		want := map[string][]string{"OPEN": {"not done yet", ""}, "CLOSED": {"done", "use OPEN"}}[value.Name]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("value %s: got description and deprecation %q, want %q", value.Name, got, want)
+		}
+	}
+}
+
+func TestEnumErrors(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegEnum(enumTestStatus("")).Value("OPEN", 1, "").Deprecate("CLOSED", "")
+	sch.RegEnum(enumTestPriority(0))
+	err := sch.Validate()
+	for _, want := range []string{"enumTestStatus.OPEN: enum value 1 cannot be converted to gographer.enumTestStatus", "enumTestStatus.CLOSED: cannot deprecate unknown enum value", "enumTestPriority: enum has no values"} {
+		if err == nil || !containsError(err.(SchemaErrors), want) {
+			t.Errorf("missing error %q, got %v", want, err)
+		}
+	}
+}
+
+func containsError(errs SchemaErrors, message string) bool {
+	for _, e := range errs {
+		if e.Error() == message {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package gographer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/net/context"
+	"reflect"
+	"runtime/debug"
+)
+
+const (
+	ErrorCode_Internal  = "INTERNAL_SERVER_ERROR"
+	ErrorCode_InvalidID = "INVALID_ID" // malformed global ID, or its type is unknown or not a node type
+)
+
+// Message sent to clients when the panic handler doesn't provide one.
+const DefaultPanicMessage = "Internal server error"
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// FieldError wraps an error returned by a resolver, extension or mutation method,
+// the executor attaches the field path and locations when it is reported.
+type FieldError struct {
+	TypeName  string
+	FieldName string
+	Err       error
+	Code      string // reported as extensions.code, if not empty
+}
+
+func (e *FieldError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Implements gqlerrors.ExtendedError
+func (e *FieldError) Extensions() map[string]interface{} {
+	if e.Code == "" {
+		return nil
+	}
+	return map[string]interface{}{"code": e.Code}
+}
+
+// SchemaError is a registration problem found by Validate.
+type SchemaError struct {
+	TypeName  string
+	FieldName string
+	Message   string
+	keepField bool // the field is still built, e.g. without a later definition or a shadowed interface method, or with a lossy int mapping
+}
+
+func (e *SchemaError) Error() string {
+	if e.FieldName == "" {
+		if e.TypeName == "" {
+			return e.Message
+		}
+		return e.TypeName + ": " + e.Message
+	}
+	return e.TypeName + "." + e.FieldName + ": " + e.Message
+}
+
+func (e *SchemaError) key() string {
+	return e.TypeName + "." + e.FieldName
+}
+
+// SchemaErrors lists every problem found by Validate.
+type SchemaErrors []*SchemaError
+
+func (errs SchemaErrors) Error() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "gographer: %d schema registration error(s):", len(errs))
+	for _, e := range errs {
+		buf.WriteString("\n\t")
+		buf.WriteString(e.Error())
+	}
+	return buf.String()
+}
+
+// Copies of errors recorded by a type before validating, with its current name.
+func (errs SchemaErrors) withTypeName(typeName string) SchemaErrors {
+	named := make(SchemaErrors, len(errs))
+	for i, e := range errs {
+		copied := *e
+		copied.TypeName = typeName
+		named[i] = &copied
+	}
+	return named
+}
+
+// Set of "Type.field" keys, used to skip invalid fields while building.
+// Problems keeping their field are left out, e.g. the field keeps its first definition.
+func (errs SchemaErrors) fieldSet() map[string]bool {
+	set := make(map[string]bool)
+	for _, e := range errs {
+		if e.FieldName != "" && !e.keepField {
+			set[e.key()] = true
+		}
+	}
+	return set
+}
+
+// PanicInfo describes a panic recovered while calling a resolver, extension or mutation method.
+type PanicInfo struct {
+	TypeName  string
+	FieldName string
+	Recovered interface{}
+	Stack     []byte
+}
+
+// PanicHandler reports a recovered panic and returns the error clients will see,
+// returning nil uses DefaultPanicMessage.
+type PanicHandler func(ctx context.Context, info *PanicInfo) error
+
+// Turn a recovered panic into a FieldError with ErrorCode_Internal.
+func (sch *SchemaInfo) recoverPanic(ctx context.Context, typeName string, fieldName string, recovered interface{}) error {
+	return sch.reportPanic(ctx, typeName, fieldName, recovered, debug.Stack())
+}
+
+// recoverPanic for a panic recovered elsewhere, e.g. in a batch func, with the stack at the panic.
+func (sch *SchemaInfo) reportPanic(ctx context.Context, typeName string, fieldName string, recovered interface{}, stack []byte) error {
+	info := &PanicInfo{
+		TypeName:  typeName,
+		FieldName: fieldName,
+		Recovered: recovered,
+		Stack:     stack,
+	}
+	sch.logger.Warn("Recovered panic", "type", typeName, "field", fieldName, "panic", recovered, "stack", string(info.Stack))
+	var err error
+	if sch.panicHandler != nil {
+		err = sch.panicHandler(ctx, info)
+	}
+	if err == nil {
+		err = errors.New(DefaultPanicMessage)
+	}
+	return &FieldError{TypeName: typeName, FieldName: fieldName, Err: err, Code: ErrorCode_Internal}
+}
+
+// Whether the last return value of the function is an error.
+func hasErrorOut(funcType reflect.Type) bool {
+	numOut := funcType.NumOut()
+	return numOut > 0 && funcType.Out(numOut-1) == errorType
+}
+
+// Number of return values excluding the trailing error.
+func numResultOut(funcType reflect.Type) int {
+	if hasErrorOut(funcType) {
+		return funcType.NumOut() - 1
+	}
+	return funcType.NumOut()
+}
+
+// Extract the trailing error from the return values of a dynamic call.
+func errorFromOut(funcType reflect.Type, outValues []reflect.Value) error {
+	if !hasErrorOut(funcType) {
+		return nil
+	}
+	errVal := outValues[len(outValues)-1]
+	if errVal.IsNil() {
+		return nil
+	}
+	return errVal.Interface().(error)
+}
+
+// Error of a field as reported to clients. FieldErrors are kept, panics of batch funcs are reported
+// with the panic handler once for all the keys of the batch, other errors are wrapped in a FieldError.
+func (sch *SchemaInfo) fieldError(ctx context.Context, typeName string, fieldName string, err error) error {
+	switch err := err.(type) {
+	case *FieldError:
+		return err
+	case *batchPanic:
+		return err.report(func() error {
+			return sch.reportPanic(ctx, typeName, fieldName, err.recovered, err.stack)
+		})
+	}
+	return &FieldError{TypeName: typeName, FieldName: fieldName, Err: err}
+}
+
+// Thunk reporting its error with the extensions, e.g. the code of a FieldError. graphql-go drops the
+// extensions of errors returned by thunks, but keeps those of a located error the thunk panics with.
+// path is the path of the field, or of the list item the thunk is completed for.
+func extendedErrorThunk(info graphql.ResolveInfo, path *graphql.ResponsePath, thunk func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		result, err := thunk()
+		if err != nil {
+			panic(graphql.NewLocatedErrorWithPath(err, graphql.FieldASTsToNodeASTs(info.FieldASTs), path.AsArray()))
+		}
+		return result, nil
+	}
+}
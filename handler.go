@@ -0,0 +1,252 @@
+package gographer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"golang.org/x/net/context"
+	"html/template"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Handler serves GraphQL over HTTP with a schema built once by SchemaInfo.NewHandler.
+//
+// GET takes query, variables (JSON) and operationName from the URL, mutations are only allowed with POST.
+// POST takes a JSON body with query, variables and operationName, an application/graphql body
+// with the query, or the same parameters form encoded.
+// Responses are JSON, with status 200 if the operation was executed, even if it has errors,
+// 400 for malformed requests and queries that don't parse or validate,
+// 405 and 415 for unsupported methods and content types, 413 for bodies larger than the limit.
+type Handler struct {
+	schema      graphql.Schema
+	graphiQL    bool
+	contextFunc func(r *http.Request) context.Context
+	maxBodySize int64
+}
+
+// Limit of POST bodies unless set by SetMaxBodySize.
+const DefaultMaxBodySize = 1 << 20
+
+// GraphQL request parameters, from the URL, a form or a JSON body.
+type httpRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// NewHandler builds the schema like GetSchema and returns a handler serving it,
+// registration errors are returned the same way, the handler is nil if no schema could be built.
+func (sch SchemaInfo) NewHandler() (*Handler, error) {
+	schema, err := sch.GetSchema()
+	if schema.QueryType() == nil {
+		return nil, err
+	}
+	return NewHandler(schema), err
+}
+
+// NewHandler returns a handler serving a schema built before.
+func NewHandler(schema graphql.Schema) *Handler {
+	return &Handler{
+		schema:      schema,
+		maxBodySize: DefaultMaxBodySize,
+		contextFunc: func(r *http.Request) context.Context {
+			return r.Context()
+		},
+	}
+}
+
+// Serve the GraphiQL IDE to browsers requesting the endpoint with GET and without query.
+func (h *Handler) SetGraphiQL(graphiQL bool) *Handler {
+	h.graphiQL = graphiQL
+	return h
+}
+
+// Set the function providing the context passed to resolvers, the request's context by default.
+func (h *Handler) SetContextFunc(contextFunc func(r *http.Request) context.Context) *Handler {
+	h.contextFunc = contextFunc
+	return h
+}
+
+// Set the largest POST body in bytes, larger requests are refused with status 413.
+func (h *Handler) SetMaxBodySize(maxBodySize int64) *Handler {
+	h.maxBodySize = maxBodySize
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req httpRequest
+	switch r.Method {
+	case "GET":
+		if h.graphiQL && r.URL.Query().Get("query") == "" && acceptsHTML(r) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			graphiQLPage.Execute(w, nil)
+			return
+		}
+		var err error
+		if req, err = requestFromValues(r.URL.Query()); err != nil {
+			writeErrors(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	case "POST":
+		status, msg := h.readPostRequest(w, r, &req)
+		if status != http.StatusOK {
+			writeErrors(w, status, msg)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeErrors(w, http.StatusMethodNotAllowed, "GraphQL requests need GET or POST")
+		return
+	}
+
+	if req.Query == "" {
+		writeErrors(w, http.StatusBadRequest, "missing query")
+		return
+	}
+	doc, errs := parseAndValidate(h.schema, req.Query)
+	if len(errs) > 0 {
+		writeJSON(w, http.StatusBadRequest, &graphql.Result{Errors: errs}) // not executed
+		return
+	}
+	if r.Method == "GET" && isMutation(doc, req.OperationName) {
+		w.Header().Set("Allow", "POST")
+		writeErrors(w, http.StatusMethodNotAllowed, "mutations need POST")
+		return
+	}
+
+	ctx := h.contextFunc(r)
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		Args:          req.Variables,
+		OperationName: req.OperationName,
+		Context:       WithLoaders(WithVariables(ctx, req.Variables)), // explicit null variables, per-request loaders
+	})
+	writeJSON(w, http.StatusOK, result) // executed, errors of resolvers and variables are in the result
+}
+
+// Document of a query, or the errors of a query that doesn't parse or validate.
+func parseAndValidate(schema graphql.Schema, query string) (*ast.Document, []gqlerrors.FormattedError) {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil, gqlerrors.FormatErrors(err)
+	}
+	if validation := graphql.ValidateDocument(&schema, doc, nil); !validation.IsValid {
+		return nil, validation.Errors
+	}
+	return doc, nil
+}
+
+func (h *Handler) readPostRequest(w http.ResponseWriter, r *http.Request, req *httpRequest) (int, string) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json", "", "application/graphql", "application/x-www-form-urlencoded":
+	default:
+		return http.StatusUnsupportedMediaType, "unsupported content type " + mediaType
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodySize))
+	if err != nil {
+		if int64(len(body)) >= h.maxBodySize {
+			return http.StatusRequestEntityTooLarge, fmt.Sprintf("request body larger than %d bytes", h.maxBodySize)
+		}
+		return http.StatusBadRequest, err.Error()
+	}
+
+	switch mediaType {
+	case "application/graphql":
+		req.Query = string(body)
+		req.OperationName = r.URL.Query().Get("operationName")
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return http.StatusBadRequest, err.Error()
+		}
+		if *req, err = requestFromValues(values); err != nil {
+			return http.StatusBadRequest, err.Error()
+		}
+	default:
+		if err := json.Unmarshal(body, req); err != nil {
+			return http.StatusBadRequest, "invalid JSON body: " + err.Error()
+		}
+	}
+	return http.StatusOK, ""
+}
+
+func requestFromValues(values map[string][]string) (httpRequest, error) {
+	get := func(key string) string {
+		if v := values[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	req := httpRequest{Query: get("query"), OperationName: get("operationName")}
+	if variables := get("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			return req, errors.New("invalid variables: " + err.Error())
+		}
+	}
+	return req, nil
+}
+
+// Whether the operation to run is a mutation.
+func isMutation(doc *ast.Document, operationName string) bool {
+	var operations []*ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+				operations = append(operations, op)
+			}
+		}
+	}
+	return len(operations) == 1 && operations[0].Operation == "mutation"
+}
+
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+var graphiQLPage = template.Must(template.New("graphiql").Parse(`<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL</title>
+  <style>body { height: 100vh; margin: 0; overflow: hidden; } #graphiql { height: 100vh; }</style>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@1.4.7/graphiql.min.css">
+  <script src="https://unpkg.com/react@17.0.2/umd/react.production.min.js"></script>
+  <script src="https://unpkg.com/react-dom@17.0.2/umd/react-dom.production.min.js"></script>
+  <script src="https://unpkg.com/graphiql@1.4.7/graphiql.min.js"></script>
+</head>
+<body>
+  <div id="graphiql">Loading...</div>
+  <script>
+    function fetcher(params) {
+      return fetch(window.location.pathname, {
+        method: "POST",
+        headers: {"Accept": "application/json", "Content-Type": "application/json"},
+        body: JSON.stringify(params),
+        credentials: "same-origin"
+      }).then(function (response) { return response.json(); });
+    }
+    ReactDOM.render(React.createElement(GraphiQL, {fetcher: fetcher}), document.getElementById("graphiql"));
+  </script>
+</body>
+</html>
+`))
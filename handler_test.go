@@ -0,0 +1,126 @@
+package gographer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type handlerTestRoot struct{}
+
+func (r *handlerTestRoot) GetGreeting() string {
+	return "hello"
+}
+
+func (r *handlerTestRoot) GetFailing() (string, error) {
+	return "", errors.New("failing")
+}
+
+type handlerTestMutation struct{}
+
+func (m *handlerTestMutation) Touch() (string, error) {
+	return "touched", nil
+}
+
+func TestHandlerStatus(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&handlerTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&handlerTestMutation{}).SetMutation().MutationField("touch", "Touch", nil, []OutputInfo{{Name: "result"}})
+	handler, err := sch.NewHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		status int
+		errors bool // in the response
+	}{
+		{"query", "POST", `{ greeting }`, http.StatusOK, false},
+		{"query with GET", "GET", `{ greeting }`, http.StatusOK, false},
+		{"resolver error", "POST", `{ failing }`, http.StatusOK, true},
+		{"resolver error with data", "POST", `{ greeting failing }`, http.StatusOK, true},
+		{"missing variable", "POST", `query($b: Boolean!) { greeting @include(if: $b) }`, http.StatusOK, true},
+		{"syntax error", "POST", `{ greeting`, http.StatusBadRequest, true},
+		{"unknown field", "POST", `{ unknown }`, http.StatusBadRequest, true},
+		{"syntax error with GET", "GET", `{ greeting`, http.StatusBadRequest, true},
+		{"empty query", "POST", ``, http.StatusBadRequest, true},
+		{"mutation", "POST", `mutation { touch(input: {clientMutationId: "1"}) { result } }`, http.StatusOK, false},
+		{"mutation with GET", "GET", `mutation { touch(input: {clientMutationId: "1"}) { result } }`, http.StatusMethodNotAllowed, true},
+	}
+	for _, test := range tests {
+		var req *http.Request
+		if test.method == "GET" {
+			req = httptest.NewRequest("GET", "/graphql?query="+url.QueryEscape(test.query), nil)
+		} else {
+			body, _ := json.Marshal(map[string]string{"query": test.query})
+			req = httptest.NewRequest("POST", "/graphql", strings.NewReader(string(body)))
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var resp struct {
+			Data   interface{}   `json:"data"`
+			Errors []interface{} `json:"errors"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%s: invalid response %q", test.name, rec.Body.String())
+			continue
+		}
+		if rec.Code != test.status || (len(resp.Errors) > 0) != test.errors {
+			t.Errorf("%s: got status %d with errors %v, want %d", test.name, rec.Code, resp.Errors, test.status)
+		}
+	}
+}
+
+func TestHandlerRequests(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&handlerTestRoot{}).SetRoot().ResolvedFields()
+	handler, err := sch.NewHandler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.SetGraphiQL(true).SetMaxBodySize(128)
+	operations := `query A { greeting } query B { failing }`
+	form := url.Values{"query": {operations}, "operationName": {"A"}}.Encode()
+	tests := []struct {
+		name        string
+		method      string
+		url         string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		response    string // start of the body
+	}{
+		{"graphql body", "POST", "/graphql", "application/graphql", "", `{ greeting }`, http.StatusOK, `{"data":{"greeting":"hello"}}`},
+		{"graphql body with operationName", "POST", "/graphql?operationName=A", "application/graphql; charset=utf-8", "", operations, http.StatusOK, `{"data":{"greeting":"hello"}}`},
+		{"form body", "POST", "/graphql", "application/x-www-form-urlencoded", "", form, http.StatusOK, `{"data":{"greeting":"hello"}}`},
+		{"JSON body with operationName", "POST", "/graphql", "application/json", "", `{"query":"` + operations + `","operationName":"B"}`, http.StatusOK, `{"data":{"failing":null},"errors":[{"message":"failing"`},
+		{"GET with operationName", "GET", "/graphql?operationName=A&query=" + url.QueryEscape(operations), "", "", "", http.StatusOK, `{"data":{"greeting":"hello"}}`},
+		{"unsupported content type", "POST", "/graphql", "text/plain", "", `{ greeting }`, http.StatusUnsupportedMediaType, `{"data":null,"errors":[{"message":"unsupported content type text/plain"`},
+		{"body too large", "POST", "/graphql", "application/graphql", "", `{ greeting ` + strings.Repeat(" ", 128) + `}`, http.StatusRequestEntityTooLarge, `{"data":null,"errors":[{"message":"request body larger than 128 bytes"`},
+		{"GraphiQL", "GET", "/graphql", "", "text/html,application/xhtml+xml", "", http.StatusOK, `<!DOCTYPE html>`},
+		{"GraphiQL with query", "GET", "/graphql?query=" + url.QueryEscape(`{ greeting }`), "", "text/html", "", http.StatusOK, `{"data":{"greeting":"hello"}}`},
+		{"no GraphiQL without HTML", "GET", "/graphql", "", "application/json", "", http.StatusBadRequest, `{"data":null,"errors":[{"message":"missing query"`},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.url, strings.NewReader(test.body))
+		if test.contentType != "" {
+			req.Header.Set("Content-Type", test.contentType)
+		}
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != test.status || !strings.HasPrefix(rec.Body.String(), test.response) {
+			t.Errorf("%s: got status %d with %q, want %d with %q", test.name, rec.Code, rec.Body.String(), test.status, test.response)
+		}
+	}
+}
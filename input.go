@@ -0,0 +1,290 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"reflect"
+)
+
+// GraphQL input object built from a Go struct used in arguments or mutation input, cached by name.
+type inputObjectInfo struct {
+	Type   reflect.Type
+	qlType *graphql.InputObject // built lazily
+}
+
+// Input object name for a Go struct by the naming strategy, Address becomes AddressInput by default.
+func (sch *SchemaInfo) inputTypeName(typ reflect.Type) string {
+	return sch.typeName(typ, TypeKind_Input)
+}
+
+// Whether the Go type has its own GraphQL type registered, which takes precedence over input objects and lists.
+func (sch *SchemaInfo) hasNamedQLType(typ reflect.Type) bool {
+	_, isScalar := sch.scalarsByType[typ]
+	_, isEnum := sch.enumsByType[typ]
+	return isScalar || isEnum
+}
+
+// toQLType for arguments and input fields, structs become input objects.
+// Pointers and Optional wrappers take the type of their value.
+// Elements of struct slices are non null, the Go value cannot be nil.
+func (sch *SchemaInfo) toQLInputType(typ reflect.Type) graphql.Input {
+	if !sch.hasNamedQLType(typ) {
+		if valueField, ok := optionalValueField(typ); ok {
+			return sch.toQLInputType(valueField.Type)
+		}
+		switch typ.Kind() {
+		case reflect.Struct:
+			return sch.inputObjectType(typ)
+		case reflect.Ptr:
+			return sch.toQLInputType(typ.Elem()) // nil when omitted
+		case reflect.Slice:
+			elemQLType := sch.toQLInputType(typ.Elem())
+			if elemQLType == nil {
+				return nil
+			}
+			if typ.Elem().Kind() == reflect.Struct && !sch.hasNamedQLType(typ.Elem()) {
+				elemQLType = graphql.NewNonNull(elemQLType)
+			}
+			return graphql.NewList(elemQLType)
+		}
+	}
+	if qlType := sch.toQLType(typ); qlType != nil {
+		return qlType
+	}
+	return nil
+}
+
+func (sch *SchemaInfo) inputObjectType(typ reflect.Type) *graphql.InputObject {
+	name := sch.inputTypeName(typ)
+	info, ok := sch.inputObjects[name]
+	if !ok {
+		info = &inputObjectInfo{Type: typ}
+		sch.inputObjects[name] = info
+	}
+	if info.qlType == nil {
+		info.qlType = graphql.NewInputObject(graphql.InputObjectConfig{
+			Name: name,
+			Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+				// thunk allows recursive input types
+				fields := make(graphql.InputObjectConfigFieldMap)
+				for i := 0; i < typ.NumField(); i++ {
+					field := typ.Field(i)
+					fieldName, ok := sch.fieldName(field)
+					if !ok {
+						continue // unexported or excluded fields are not part of the input
+					}
+					fieldQLType, defaultValue := sch.inputFieldQLType(field)
+					fields[fieldName] = &graphql.InputObjectFieldConfig{
+						Type:         fieldQLType,
+						DefaultValue: defaultValue,
+					}
+				}
+				return fields
+			}),
+		})
+	}
+	return info.qlType
+}
+
+// GraphQL type and default value of an AutoArgs or input struct field from its type and tags,
+// the nonNull option of TAG_GraphQL is the same as the TAG_NonNull tag.
+// Fields tagged with TAG_GlobalID are ID!, or [ID!]! for lists, pointers and Optional stay nullable.
+func (sch *SchemaInfo) inputFieldQLType(field reflect.StructField) (graphql.Input, interface{}) {
+	var qlType graphql.Input
+	if globalIDTag := field.Tag.Get(TAG_GlobalID); globalIDTag != "" {
+		qlType = graphql.ID
+		if isStringSlice(field.Type) {
+			qlType = graphql.NewList(graphql.NewNonNull(graphql.ID))
+		}
+		if _, isOptional := optionalValueField(field.Type); !isOptional && field.Type.Kind() != reflect.Ptr {
+			qlType = graphql.NewNonNull(qlType)
+		}
+	} else {
+		qlType = sch.toQLInputType(field.Type)
+		if nonNullTag := field.Tag.Get(TAG_NonNull); nonNullTag == "true" || hasTagOption(field, "nonNull") {
+			qlType = graphql.NewNonNull(qlType)
+		}
+	}
+	var defaultValue interface{} = nil
+	if defTag := field.Tag.Get(TAG_DefaultValue); defTag != "" {
+		defaultValue = sch.parseDefaultValue(defTag, field.Type)
+	}
+	return qlType, defaultValue
+}
+
+// Whether the Go type is a list of strings, behind a pointer or Optional.
+func isStringSlice(typ reflect.Type) bool {
+	if valueField, ok := optionalValueField(typ); ok {
+		typ = valueField.Type
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.String
+}
+
+// Whether a field tagged with TAG_GlobalID holds a string or a list of strings, behind a pointer or Optional.
+func isGlobalIDFieldType(typ reflect.Type) bool {
+	if isStringSlice(typ) {
+		return true
+	}
+	if valueField, ok := optionalValueField(typ); ok {
+		typ = valueField.Type
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.String
+}
+
+// Local IDs from global IDs of the given type, for an ID or a list of IDs.
+func decodeGlobalIDs(v interface{}, typeName string) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		resolvedID := relay.FromGlobalID(v)
+		if resolvedID == nil {
+			return nil, fmt.Errorf("invalid global ID %q", v)
+		}
+		if resolvedID.Type != typeName {
+			return nil, fmt.Errorf("global ID %q is a %s ID, expected %s", v, resolvedID.Type, typeName)
+		}
+		return resolvedID.ID, nil
+	case []interface{}:
+		ids := make([]interface{}, len(v))
+		for i, item := range v {
+			id, err := decodeGlobalIDs(item, typeName)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %v", i, err)
+			}
+			ids[i] = id
+		}
+		return ids, nil
+	default:
+		return nil, fmt.Errorf("cannot use %T as global ID", v)
+	}
+}
+
+// Go value of type t from a GraphQL input value, converting named types, numbers, lists and input objects.
+// Nil becomes the zero value. given is the value as given in the query, see givenArgs,
+// it tells omitted input fields from explicit nulls for pointer and Optional fields.
+func (sch *SchemaInfo) inputValue(v interface{}, given interface{}, t reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(t), nil
+	}
+	val := reflect.ValueOf(v)
+	if val.Type().AssignableTo(t) {
+		return val, nil
+	}
+	if t.Kind() == reflect.Ptr {
+		elemVal, err := sch.inputValue(v, given, t.Elem())
+		if err != nil {
+			return reflect.Zero(t), err
+		}
+		ptrVal := reflect.New(t.Elem())
+		ptrVal.Elem().Set(elemVal)
+		return ptrVal, nil
+	}
+	if t.Kind() == reflect.Slice && val.Kind() == reflect.Slice {
+		givenList, _ := given.([]interface{})
+		out := reflect.MakeSlice(t, val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			var itemGiven interface{} = val.Index(i).Interface()
+			if len(givenList) == val.Len() {
+				itemGiven = givenList[i]
+			}
+			elemVal, err := sch.inputValue(val.Index(i).Interface(), itemGiven, t.Elem())
+			if err != nil {
+				return out, fmt.Errorf("item %d: %v", i, err)
+			}
+			out.Index(i).Set(elemVal)
+		}
+		return out, nil
+	}
+	if t.Kind() == reflect.Struct && val.Kind() == reflect.Map {
+		inputMap, ok := v.(map[string]interface{})
+		if !ok {
+			return reflect.Zero(t), fmt.Errorf("cannot use %T as %v", v, t)
+		}
+		givenMap, hasGivenMap := given.(map[string]interface{})
+		out := reflect.New(t).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldName, ok := sch.fieldName(field)
+			if !ok {
+				continue
+			}
+			fieldInput, hasInput := inputMap[fieldName]
+			fieldGiven, isGiven := fieldInput, hasInput
+			if hasGivenMap {
+				fieldGiven, isGiven = givenMap[fieldName]
+			}
+			if err := sch.assignInputField(out.Field(i), field, fieldInput, fieldGiven, isGiven); err != nil {
+				return out, fmt.Errorf("field %s: %v", fieldName, err)
+			}
+		}
+		return out, nil
+	}
+	if isIntKind(t.Kind()) && (kindClass(val.Kind()) == "number" || val.Kind() == reflect.String) {
+		return convertInt(val, t) // Long and BigInt values, or wide integers mapped to String
+	}
+	if kindClass(val.Kind()) != "" && kindClass(val.Kind()) == kindClass(t.Kind()) {
+		return val.Convert(t), nil
+	}
+	return reflect.Zero(t), fmt.Errorf("cannot use %T as %v", v, t)
+}
+
+// Assign a GraphQL input value to a settable Go value, see inputValue.
+// isGiven tells whether the argument or input field appeared in the query, with given as its value.
+// Explicit nulls leave pointers nil even when there is a default value, Optional fields get their state.
+func (sch *SchemaInfo) assignInput(dst reflect.Value, v interface{}, given interface{}, isGiven bool) error {
+	isNull := isGiven && given == nil
+	if isNull {
+		v = nil // graphql-go replaces null with the default value
+	}
+	if valueField, ok := optionalValueField(dst.Type()); ok {
+		state := OptionalState_Set
+		if isNull {
+			state = OptionalState_Null
+		} else if v == nil {
+			state = OptionalState_Omitted
+		}
+		dst.Field(0).Set(reflect.ValueOf(Optional{State: state}))
+		dst = dst.FieldByIndex(valueField.Index)
+	}
+	val, err := sch.inputValue(v, given, dst.Type())
+	if err != nil {
+		return err
+	}
+	dst.Set(val)
+	return nil
+}
+
+// assignInput for a field of an AutoArgs or input struct, global IDs are decoded first.
+func (sch *SchemaInfo) assignInputField(dst reflect.Value, field reflect.StructField, v interface{}, given interface{}, isGiven bool) error {
+	if globalIDTag := field.Tag.Get(TAG_GlobalID); globalIDTag != "" {
+		var err error
+		if v, err = decodeGlobalIDs(v, globalIDTag); err != nil {
+			return err
+		}
+	}
+	return sch.assignInput(dst, v, given, isGiven)
+}
+
+// Kinds which can be converted into each other without changing meaning.
+func kindClass(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,213 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"strings"
+	"testing"
+)
+
+type inputTestAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city" def:"Paris"`
+}
+
+type inputTestLineItem struct {
+	SKU      string `json:"sku" graphql:",nonNull"`
+	Quantity int    `json:"quantity" def:"1"`
+}
+
+type inputTestCategory struct {
+	Name     string              `json:"name"`
+	Children []inputTestCategory `json:"children"`
+}
+
+type inputTestOrderArgs struct {
+	Address  *inputTestAddress   `json:"address"`
+	Items    []inputTestLineItem `json:"items" nonNull:"true"`
+	Category inputTestCategory   `json:"category"`
+}
+
+func (args inputTestOrderArgs) String() string {
+	var parts []string
+	if args.Address != nil {
+		parts = append(parts, args.Address.Street+", "+args.Address.City)
+	}
+	for _, item := range args.Items {
+		parts = append(parts, fmt.Sprint(item.Quantity, "x", item.SKU))
+	}
+	var categories func(category inputTestCategory) string
+	categories = func(category inputTestCategory) string {
+		var children []string
+		for _, child := range category.Children {
+			children = append(children, categories(child))
+		}
+		return category.Name + "(" + strings.Join(children, " ") + ")"
+	}
+	return strings.Join(append(parts, categories(args.Category)), "; ")
+}
+
+type inputTestRoot struct{}
+
+func (r *inputTestRoot) GetOrder(args inputTestOrderArgs) string {
+	return args.String()
+}
+
+type inputTestMutation struct{}
+
+type inputTestOrderPayload struct {
+	Order string `json:"order"`
+}
+
+func (m *inputTestMutation) PlaceOrder(in inputTestOrderArgs) (*inputTestOrderPayload, error) {
+	return &inputTestOrderPayload{Order: in.String()}, nil
+}
+
+func TestNestedInputObjects(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&inputTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&inputTestMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{`{ order(address: {street: "Main St"}, items: [{sku: "a"}, {sku: "b", quantity: 3}], category: {name: "root", children: [{name: "leaf"}]}) }`, nil,
+			`{"data":{"order":"Main St, Paris; 1xa; 3xb; root(leaf())"}}`},
+		{`query($address: inputTestAddressInput, $items: [inputTestLineItemInput!]!) { order(address: $address, items: $items) }`,
+			map[string]interface{}{"address": map[string]interface{}{"street": "Elm St", "city": "Lyon"}, "items": []interface{}{map[string]interface{}{"sku": "c"}}},
+			`{"data":{"order":"Elm St, Lyon; 1xc; ()"}}`},
+		{`mutation { placeOrder(input: {items: [{sku: "d", quantity: 2}], category: {name: "x"}}) { order } }`, nil,
+			`{"data":{"placeOrder":{"order":"2xd; x()"}}}`},
+		{`{ order(items: [{quantity: 2}]) }`, nil,
+			`{"data":null,"errors":[{"message":"Argument \"items\" has invalid value [{quantity: 2}].\nIn element #1: In field \"sku\": Expected \"String!\", found null.","locations":[{"line":1,"column":16}]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, test.variables); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+	wantArgs := map[string]string{
+		"address":  "inputTestAddressInput",
+		"items":    "[inputTestLineItemInput!]!",
+		"category": "inputTestCategoryInput",
+	}
+	for _, arg := range schema.QueryType().Fields()["order"].Args {
+		if arg.Type.String() != wantArgs[arg.Name()] {
+			t.Errorf("argument %s: got type %s, want %s", arg.Name(), arg.Type, wantArgs[arg.Name()])
+		}
+	}
+	childrenType := schema.Type("inputTestCategoryInput").(*graphql.InputObject).Fields()["children"].Type
+	if childrenType.String() != "[inputTestCategoryInput!]" {
+		t.Errorf("got recursive field type %s", childrenType)
+	}
+}
+
+type inputTestNode struct {
+	ID string `json:"id"`
+}
+
+type inputTestOwnerRef struct {
+	Owner string `json:"owner" gqlid:"inputTestNode"`
+}
+
+type inputTestShowArgs struct {
+	One  string             `json:"one" gqlid:"inputTestNode"`
+	Many []string           `json:"many" gqlid:"inputTestNode"`
+	Opt  *string            `json:"opt" gqlid:"inputTestNode"`
+	Ref  *inputTestOwnerRef `json:"ref"`
+}
+
+type inputTestIDRoot struct{}
+
+func (r *inputTestIDRoot) GetShow(args inputTestShowArgs) string {
+	show := args.One + " " + strings.Join(args.Many, ",")
+	if args.Opt != nil {
+		show += " opt " + *args.Opt
+	}
+	if args.Ref != nil {
+		show += " ref " + args.Ref.Owner
+	}
+	return show
+}
+
+type inputTestIDMutation struct{}
+
+type inputTestRemoveInput struct {
+	ID string `json:"id" gqlid:"inputTestNode"`
+}
+
+type inputTestRemovePayload struct {
+	RemovedID string `json:"removedID"`
+}
+
+func (m *inputTestIDMutation) Remove(in inputTestRemoveInput) (*inputTestRemovePayload, error) {
+	return &inputTestRemovePayload{RemovedID: in.ID}, nil
+}
+
+func TestGlobalIDArgs(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&inputTestNode{}).IDField("id", nil)
+	sch.RegType(&inputTestIDRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&inputTestIDMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id1, id2 := relay.ToGlobalID("inputTestNode", "1"), relay.ToGlobalID("inputTestNode", "2")
+	other := relay.ToGlobalID("inputTestIDRoot", "1")
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{fmt.Sprintf(`{ show(one: %q, many: [%q, %q], opt: %q, ref: {owner: %q}) }`, id1, id1, id2, id2, id1),
+			`{"data":{"show":"1 1,2 opt 2 ref 1"}}`},
+		{fmt.Sprintf(`mutation { remove(input: {id: %q}) { removedID } }`, id2),
+			`{"data":{"remove":{"removedID":"2"}}}`},
+		{fmt.Sprintf(`{ show(one: %q, many: []) }`, other),
+			`{"data":{"show":null},"errors":[{"message":"argument one: global ID \"` + other + `\" is a inputTestIDRoot ID, expected inputTestNode","locations":[{"line":1,"column":3}],"path":["show"]}]}`},
+		{fmt.Sprintf(`{ show(one: %q, many: [%q, "garbage"]) }`, id1, id1),
+			`{"data":{"show":null},"errors":[{"message":"argument many: item 1: invalid global ID \"garbage\"","locations":[{"line":1,"column":3}],"path":["show"]}]}`},
+		{fmt.Sprintf(`mutation { remove(input: {id: %q}) { removedID } }`, other),
+			`{"data":{"remove":null},"errors":[{"message":"input field id: global ID \"` + other + `\" is a inputTestIDRoot ID, expected inputTestNode","locations":[{"line":1,"column":12}],"path":["remove"]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, nil); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+	wantArgs := map[string]string{"one": "ID!", "many": "[ID!]!", "opt": "ID", "ref": "inputTestOwnerRefInput"}
+	for _, arg := range schema.QueryType().Fields()["show"].Args {
+		if arg.Type.String() != wantArgs[arg.Name()] {
+			t.Errorf("argument %s: got type %s, want %s", arg.Name(), arg.Type, wantArgs[arg.Name()])
+		}
+	}
+}
+
+type inputTestBadIDArgs struct {
+	Number  int    `json:"number" gqlid:"inputTestNode"`
+	Unknown string `json:"unknown" gqlid:"Missing"`
+}
+
+type inputTestBadIDRoot struct{}
+
+func (r *inputTestBadIDRoot) GetBad(args inputTestBadIDArgs) string {
+	return ""
+}
+
+func TestGlobalIDArgErrors(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&inputTestNode{}).IDField("id", nil)
+	sch.RegType(&inputTestBadIDRoot{}).SetRoot().ResolvedFields()
+	err := sch.Validate()
+	for _, want := range []string{"gqlid tag needs a string or []string field, got int", "gqlid type Missing is not registered, use RegType"} {
+		if !strings.Contains(fmt.Sprint(err), want) {
+			t.Errorf("missing error %q, got %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"reflect"
+	"runtime"
+	"sort"
+)
+
+// FieldMapping tells how a GraphQL field is implemented in Go, see SchemaInfo.Inspect.
+type FieldMapping struct {
+	TypeName  string // GraphQL type
+	FieldName string
+	Source    string // Go struct field, method or func resolving the field
+	GoType    string // Go type of the struct field or func
+	QLType    string // GraphQL type, empty if the field is left out of the schema
+	Args      []ArgMapping
+}
+
+// ArgMapping is a GraphQL argument or mutation input field and the Go type it's bound to.
+type ArgMapping struct {
+	Name   string
+	GoType string // empty for arguments without Go parameter, e.g. connection arguments
+	QLType string
+}
+
+// Inspect builds the schema like GetSchema and lists the GraphQL fields of the registered types
+// with the Go struct fields and methods behind them, in registration order.
+// Registration errors are returned the same way as by GetSchema.
+func (sch SchemaInfo) Inspect() ([]FieldMapping, error) {
+	schema, err := sch.GetSchema()
+	var mappings []FieldMapping
+	for _, typ := range sch.types {
+		if typ.isMutationType {
+			mappings = append(mappings, sch.inspectMutationType(typ, schema)...)
+			continue
+		}
+		var qlFields graphql.FieldDefinitionMap
+		if qlType, ok := schema.TypeMap()[typ.Name].(*graphql.Object); ok && schema.QueryType() != nil {
+			qlFields = qlType.Fields()
+		}
+		add := func(name string, source string, goType reflect.Type, goArgTypes map[string]reflect.Type) {
+			mapping := FieldMapping{TypeName: typ.Name, FieldName: name, Source: source}
+			if goType != nil {
+				mapping.GoType = goType.String()
+			}
+			if qlField, ok := qlFields[name]; ok {
+				mapping.QLType = qlField.Type.String()
+				mapping.Args = inspectArgs(qlField.Args, goArgTypes)
+			}
+			mappings = append(mappings, mapping)
+		}
+
+		var manualNames []string
+		for name := range typ.fields {
+			manualNames = append(manualNames, name)
+		}
+		sort.Strings(manualNames)
+		for _, name := range manualNames {
+			add(name, "graphql.Field", nil, nil)
+		}
+		for _, sf := range typ.simpleFields {
+			if _, ok := qlFields[sf.Name]; sf.auto && !ok {
+				continue // not included
+			}
+			add(sf.Name, "field "+typ.Type.Name()+"."+sf.goName, sf.GoType, nil)
+		}
+		resolvedFields := append(typ.resolvedFields[:len(typ.resolvedFields):len(typ.resolvedFields)], sch.interfaceFields(typ)...)
+		for _, rf := range resolvedFields {
+			if _, ok := qlFields[rf.Name]; rf.autoSimple && !ok {
+				continue
+			}
+			if rf.autoSimple {
+				add(rf.Name, "embedded field "+rf.Name, rf.ManualGoType, nil)
+				continue
+			}
+			funcType, found := typ.resolvedFuncType(rf)
+			source := fmt.Sprintf("method (*%s).%s", typ.Type.Name(), rf.MethodName)
+			if rf.ExtensionFunc != nil {
+				source = "extension func"
+				if found {
+					source += " " + runtime.FuncForPC(reflect.ValueOf(rf.ExtensionFunc).Pointer()).Name()
+				}
+			}
+			if !found {
+				add(rf.Name, source, nil, nil)
+				continue
+			}
+			add(rf.Name, source, funcType, sch.goArgTypes(funcType, rf.AutoArgs, rf.Args))
+		}
+	}
+	return mappings, err
+}
+
+// Mutation fields with their input fields as arguments.
+func (sch *SchemaInfo) inspectMutationType(typ *TypeInfo, schema graphql.Schema) []FieldMapping {
+	var qlFields graphql.FieldDefinitionMap
+	if mutationType := schema.MutationType(); mutationType != nil {
+		qlFields = mutationType.Fields()
+	}
+	var mappings []FieldMapping
+	for _, mf := range typ.mutationFields {
+		mapping := FieldMapping{
+			TypeName:  typ.Name,
+			FieldName: mf.Name,
+			Source:    fmt.Sprintf("method (*%s).%s", typ.Type.Name(), mf.MethodName),
+		}
+		method, found := typ.findMethod(mf.MethodName)
+		if found {
+			mapping.GoType = method.Func.Type().String()
+		}
+		if qlField, ok := qlFields[mf.Name]; ok {
+			mapping.QLType = qlField.Type.String()
+			var goTypes map[string]reflect.Type
+			if found {
+				goTypes = sch.goArgTypes(method.Func.Type(), mf.AutoArgs, mf.Args)
+			}
+			for _, arg := range qlField.Args {
+				if input, ok := graphql.GetNullable(arg.Type).(*graphql.InputObject); ok {
+					var inputArgs []*graphql.Argument
+					for name, field := range input.Fields() {
+						inputArgs = append(inputArgs, &graphql.Argument{PrivateName: name, Type: field.Type})
+					}
+					mapping.Args = inspectArgs(inputArgs, goTypes)
+				}
+			}
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings
+}
+
+func inspectArgs(args []*graphql.Argument, goTypes map[string]reflect.Type) []ArgMapping {
+	var mappings []ArgMapping
+	for _, arg := range args {
+		mapping := ArgMapping{Name: arg.Name(), QLType: arg.Type.String()}
+		if goType, ok := goTypes[arg.Name()]; ok {
+			mapping.GoType = goType.String()
+		}
+		mappings = append(mappings, mapping)
+	}
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Name < mappings[j].Name })
+	return mappings
+}
+
+// Go types of the GraphQL arguments of a method or extension func by argument name.
+func (sch *SchemaInfo) goArgTypes(funcType reflect.Type, autoArgs bool, args []ArgInfo) map[string]reflect.Type {
+	goTypes := make(map[string]reflect.Type)
+	argIndex := firstArgIndex(funcType)
+	if autoArgs {
+		if numArgIn(funcType) == argIndex+1 && funcType.In(argIndex).Kind() == reflect.Struct {
+			argStructType := funcType.In(argIndex)
+			for i := 0; i < argStructType.NumField(); i++ {
+				if argName, ok := sch.fieldName(argStructType.Field(i)); ok {
+					goTypes[argName] = argStructType.Field(i).Type
+				}
+			}
+		}
+	} else {
+		for i := argIndex; i < numArgIn(funcType) && i-argIndex < len(args); i++ {
+			goTypes[args[i-argIndex].Name] = funcType.In(i)
+		}
+	}
+	if hasPageArgs(funcType) {
+		for name := range relay.ConnectionArgs {
+			goTypes[name] = pageArgsType
+		}
+	}
+	return goTypes
+}
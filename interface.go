@@ -0,0 +1,275 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"reflect"
+)
+
+// InterfaceInfo maps a Go interface to a GraphQL interface, its exported methods are the fields.
+// Registered types implementing the Go interface (with pointer receiver) are its implementors.
+type InterfaceInfo struct {
+	Name        string
+	Type        reflect.Type
+	Description string
+	errors      SchemaErrors
+}
+
+// UnionInfo maps a Go interface to a GraphQL union of registered types.
+type UnionInfo struct {
+	Name        string
+	Type        reflect.Type
+	Description string
+	members     []reflect.Type // registered types implementing Type if empty
+	errors      SchemaErrors
+}
+
+// Register a Go interface as GraphQL interface, pass a nil pointer to it, e.g. RegInterface((*Searchable)(nil)).
+// Methods named GetXxx or Xxx become field xxx, with AutoArgs like ResolvedFields.
+func (sch *SchemaInfo) RegInterface(instance interface{}) *InterfaceInfo {
+	type_ := interfaceType(instance)
+	iface := &InterfaceInfo{
+		Name: sch.typeName(type_, TypeKind_Interface),
+		Type: type_,
+	}
+	if type_.Kind() != reflect.Interface {
+		iface.errors = append(iface.errors, &SchemaError{Message: fmt.Sprintf("RegInterface needs a pointer to an interface type, got %v", type_)})
+	}
+	sch.interfaces = append(sch.interfaces, iface)
+	sch.interfacesByType[type_] = iface
+	return iface
+}
+
+// Register a Go interface as GraphQL union, pass a nil pointer to it, e.g. RegUnion((*SearchResult)(nil), Post{}, User{}).
+// Without members every registered type implementing the Go interface is a member.
+func (sch *SchemaInfo) RegUnion(instance interface{}, members ...interface{}) *UnionInfo {
+	type_ := interfaceType(instance)
+	union := &UnionInfo{
+		Name: sch.typeName(type_, TypeKind_Union),
+		Type: type_,
+	}
+	if type_.Kind() != reflect.Interface {
+		union.errors = append(union.errors, &SchemaError{Message: fmt.Sprintf("RegUnion needs a pointer to an interface type, got %v", type_)})
+	}
+	for _, member := range members {
+		memberType := reflect.TypeOf(member)
+		if memberType.Kind() == reflect.Ptr {
+			memberType = memberType.Elem()
+		}
+		union.members = append(union.members, memberType)
+	}
+	sch.unions = append(sch.unions, union)
+	sch.unionsByType[type_] = union
+	return union
+}
+
+func interfaceType(instance interface{}) reflect.Type {
+	type_ := reflect.TypeOf(instance)
+	if type_.Kind() == reflect.Ptr && type_.Elem().Kind() == reflect.Interface {
+		return type_.Elem()
+	}
+	return type_
+}
+
+func (iface *InterfaceInfo) SetName(name string) *InterfaceInfo {
+	iface.Name = name
+	return iface
+}
+
+func (iface *InterfaceInfo) SetDescription(description string) *InterfaceInfo {
+	iface.Description = description
+	return iface
+}
+
+func (union *UnionInfo) SetName(name string) *UnionInfo {
+	union.Name = name
+	return union
+}
+
+func (union *UnionInfo) SetDescription(description string) *UnionInfo {
+	union.Description = description
+	return union
+}
+
+// Fields of the interface, one resolved field with AutoArgs for each exported method,
+// XxxConnection methods are connection field xxx.
+func (iface *InterfaceInfo) fields(sch *SchemaInfo) []ResolvedFieldInfo {
+	var fields []ResolvedFieldInfo
+	if iface.Type.Kind() != reflect.Interface {
+		return fields
+	}
+	for i := 0; i < iface.Type.NumMethod(); i++ {
+		method := iface.Type.Method(i)
+		if method.PkgPath != "" {
+			continue // unexported marker method
+		}
+		fieldName, isConnection := sch.methodFieldName(method.Name)
+		fields = append(fields, ResolvedFieldInfo{
+			Name:         fieldName,
+			MethodName:   method.Name,
+			AutoArgs:     true,
+			IsConnection: isConnection,
+		})
+	}
+	return fields
+}
+
+// Function type of an interface method with the interface as receiver, like the method of a struct type.
+func (iface *InterfaceInfo) methodType(methodName string) reflect.Type {
+	method, _ := iface.Type.MethodByName(methodName)
+	ins := []reflect.Type{iface.Type}
+	for i := 0; i < method.Type.NumIn(); i++ {
+		ins = append(ins, method.Type.In(i))
+	}
+	var outs []reflect.Type
+	for i := 0; i < method.Type.NumOut(); i++ {
+		outs = append(outs, method.Type.Out(i))
+	}
+	return reflect.FuncOf(ins, outs, method.Type.IsVariadic())
+}
+
+// Whether the registered type implements the Go interface.
+func (typ *TypeInfo) implements(ifaceType reflect.Type) bool {
+	return ifaceType.Kind() == reflect.Interface && !typ.isRootType && !typ.isMutationType &&
+		reflect.PtrTo(typ.Type).Implements(ifaceType)
+}
+
+// Member types of the union, registered types implementing the Go interface unless given explicitly.
+func (sch *SchemaInfo) unionMembers(union *UnionInfo) []*TypeInfo {
+	var members []*TypeInfo
+	if len(union.members) > 0 {
+		for _, memberType := range union.members {
+			if typ, ok := sch.typesByType[memberType]; ok {
+				members = append(members, typ)
+			}
+		}
+		return members
+	}
+	for _, typ := range sch.types {
+		if typ.implements(union.Type) {
+			members = append(members, typ)
+		}
+	}
+	return members
+}
+
+// Interface fields a registered type doesn't define itself, resolved by calling its methods.
+func (sch *SchemaInfo) interfaceFields(typ *TypeInfo) []ResolvedFieldInfo {
+	var fields []ResolvedFieldInfo
+	added := make(map[string]bool)
+	for _, iface := range sch.interfaces {
+		if !typ.implements(iface.Type) {
+			continue
+		}
+		for _, rf := range iface.fields(sch) {
+			if !typ.hasField(rf.Name) && !added[rf.Name] {
+				fields = append(fields, rf)
+				added[rf.Name] = true
+			}
+		}
+	}
+	return fields
+}
+
+func (sch *SchemaInfo) processInterfaceType(
+	iface *InterfaceInfo,
+	qlTypes map[string]*graphql.Object,
+	qlConns map[string]*relay.GraphQLConnectionDefinitions) *graphql.Interface {
+
+	return graphql.NewInterface(graphql.InterfaceConfig{
+		Name:        iface.Name,
+		Description: iface.Description,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := make(graphql.Fields)
+			for _, rf := range iface.fields(sch) {
+				if sch.invalidFields[iface.Name+"."+rf.Name] {
+					continue // reported by validation
+				}
+				funcType := iface.methodType(rf.MethodName)
+				returnQLType, qlTypeKind := sch.getComplexQLType(funcType.Out(0), rf.Name, "", rf.IsConnection, qlTypes, qlConns)
+				fieldArgs := sch.buildFieldArgs(funcType, rf.AutoArgs, rf.Args)
+				if qlTypeKind == QLTypeKind_Connection {
+					fieldArgs = relay.NewConnectionArgs(fieldArgs)
+				}
+				fields[rf.Name] = &graphql.Field{
+					Type: returnQLType,
+					Args: fieldArgs,
+				}
+			}
+			return fields
+		}),
+		ResolveType: func(value interface{}, info graphql.ResolveInfo) *graphql.Object {
+			return sch.resolveObjectType(value, info, qlTypes)
+		},
+	})
+}
+
+func (sch *SchemaInfo) processUnionType(
+	union *UnionInfo,
+	qlTypes map[string]*graphql.Object) *graphql.Union {
+
+	return graphql.NewUnion(graphql.UnionConfig{
+		Name:        union.Name,
+		Description: union.Description,
+		Types: graphql.UnionTypesThunk(func() []*graphql.Object {
+			var memberQLTypes []*graphql.Object
+			for _, typ := range sch.unionMembers(union) {
+				if qlType, ok := qlTypes[typ.Name]; ok {
+					memberQLTypes = append(memberQLTypes, qlType)
+				}
+			}
+			return memberQLTypes
+		}),
+		ResolveType: func(value interface{}, info graphql.ResolveInfo) *graphql.Object {
+			return sch.resolveObjectType(value, info, qlTypes)
+		},
+	})
+}
+
+// Object type of a resolved value for interfaces, unions and the node interface, see registeredType.
+// Values of unregistered types fail the field with an error naming the Go type.
+func (sch *SchemaInfo) resolveObjectType(value interface{}, info graphql.ResolveInfo, qlTypes map[string]*graphql.Object) *graphql.Object {
+	if typ := sch.registeredType(reflect.ValueOf(value)); typ != nil {
+		if qlType, ok := qlTypes[typ.Name]; ok {
+			return qlType
+		}
+	}
+	sch.logger.Warn("Cannot resolve type", "value", value)
+	var parentName string
+	if info.ParentType != nil {
+		parentName = info.ParentType.Name()
+	}
+	panic(&FieldError{ // reported for the field by the executor
+		TypeName:  parentName,
+		FieldName: info.FieldName,
+		Err:       fmt.Errorf("cannot resolve the GraphQL type of %T, register it with RegType", value),
+	})
+}
+
+// Registered type of a value, pointers and interfaces are dereferenced, values of unregistered
+// struct types resolve to the registered type they embed.
+func (sch *SchemaInfo) registeredType(v reflect.Value) *TypeInfo {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	if typ, ok := sch.typesByType[v.Type()]; ok {
+		return typ
+	}
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.Anonymous && field.PkgPath == "" {
+				if typ := sch.registeredType(v.Field(i)); typ != nil {
+					return typ
+				}
+			}
+		}
+	}
+	return nil
+}
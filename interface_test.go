@@ -0,0 +1,236 @@
+package gographer
+
+import (
+	"github.com/graphql-go/graphql"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type ifaceTestSearchable interface {
+	Title() string
+	GetScore(args ifaceTestScoreArgs) int
+}
+
+type ifaceTestScoreArgs struct {
+	Boost int `json:"boost"`
+}
+
+type ifaceTestResult interface {
+	isResult()
+}
+
+type ifaceTestPost struct {
+	Body string `json:"body"`
+}
+
+func (p *ifaceTestPost) Title() string {
+	return "post " + p.Body
+}
+
+func (p *ifaceTestPost) GetScore(args ifaceTestScoreArgs) int {
+	return 1 + args.Boost
+}
+
+func (p *ifaceTestPost) isResult() {}
+
+type ifaceTestPerson struct {
+	Name string `json:"name"`
+}
+
+func (p *ifaceTestPerson) Title() string {
+	return "person " + p.Name
+}
+
+func (p *ifaceTestPerson) GetScore(args ifaceTestScoreArgs) int {
+	return 2 * args.Boost
+}
+
+func (p *ifaceTestPerson) isResult() {}
+
+type ifaceTestUnregistered struct{}
+
+func (u *ifaceTestUnregistered) Title() string {
+	return ""
+}
+
+func (u *ifaceTestUnregistered) GetScore(args ifaceTestScoreArgs) int {
+	return 0
+}
+
+type ifaceTestRoot struct{}
+
+func (r *ifaceTestRoot) GetSearch() []ifaceTestSearchable {
+	return []ifaceTestSearchable{&ifaceTestPost{Body: "a"}, &ifaceTestPerson{Name: "ann"}}
+}
+
+func (r *ifaceTestRoot) GetResults() []ifaceTestResult {
+	return []ifaceTestResult{&ifaceTestPerson{Name: "bob"}, &ifaceTestPost{Body: "b"}}
+}
+
+func (r *ifaceTestRoot) GetUnknown() ifaceTestSearchable {
+	return &ifaceTestUnregistered{}
+}
+
+func newInterfaceTestSchema() *SchemaInfo {
+	sch := NewSchemaInfo()
+	sch.RegType(&ifaceTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegInterface((*ifaceTestSearchable)(nil))
+	sch.RegUnion((*ifaceTestResult)(nil))
+	sch.RegType(&ifaceTestPost{}).SetNonNode().SimpleFields()
+	sch.RegType(&ifaceTestPerson{}).SetNonNode().SimpleFields()
+	return sch
+}
+
+func TestInterfaceResolution(t *testing.T) {
+	schema, err := newInterfaceTestSchema().GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			`{ search { __typename title score(boost: 3) ... on ifaceTestPost { body } ... on ifaceTestPerson { name } } }`,
+			`{"data":{"search":[` +
+				`{"__typename":"ifaceTestPost","body":"a","score":4,"title":"post a"},` +
+				`{"__typename":"ifaceTestPerson","name":"ann","score":6,"title":"person ann"}]}}`,
+		},
+		{
+			`{ results { __typename ... on ifaceTestPost { body } ... on ifaceTestPerson { name } } }`,
+			`{"data":{"results":[{"__typename":"ifaceTestPerson","name":"bob"},{"__typename":"ifaceTestPost","body":"b"}]}}`,
+		},
+		{
+			`{ unknown { title } }`,
+			`{"data":{"unknown":null},"errors":[{"message":"cannot resolve the GraphQL type of *gographer.ifaceTestUnregistered, register it with RegType","locations":[{"line":1,"column":3}],"path":["unknown"]}]}`,
+		},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, nil); got != test.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", test.query, got, test.want)
+		}
+	}
+	for name, kind := range map[string]string{"ifaceTestSearchable": "INTERFACE", "ifaceTestResult": "UNION"} {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ __type(name: "` + name + `") { kind possibleTypes { name } } }`})
+		ttype, _ := result.Data.(map[string]interface{})["__type"].(map[string]interface{})
+		var possibleTypes []string
+		for _, possibleType := range ttype["possibleTypes"].([]interface{}) {
+			possibleTypes = append(possibleTypes, possibleType.(map[string]interface{})["name"].(string))
+		}
+		sort.Strings(possibleTypes)
+		if ttype["kind"] != kind || !reflect.DeepEqual(possibleTypes, []string{"ifaceTestPerson", "ifaceTestPost"}) {
+			t.Errorf("%s: got %v %v", name, ttype["kind"], possibleTypes)
+		}
+	}
+}
+
+type ifaceTestShadowing struct {
+	Heading string `json:"title"`
+}
+
+func (s *ifaceTestShadowing) Title() string {
+	return "method"
+}
+
+func (s *ifaceTestShadowing) GetScore(args ifaceTestScoreArgs) int {
+	return 0
+}
+
+func TestInterfaceFieldShadowing(t *testing.T) {
+	sch := newInterfaceTestSchema()
+	sch.RegType(&ifaceTestShadowing{}).SetNonNode().SimpleFields()
+	err := sch.Validate()
+	if !hasSchemaError(err, "ifaceTestShadowing", "field shadows method Title of interface ifaceTestSearchable") {
+		t.Errorf("shadowing field not reported: %v", err)
+	}
+	if errs, _ := err.(SchemaErrors); len(errs) != 1 {
+		t.Errorf("got errors %v, want only the shadowing field", err)
+	}
+
+	sch = newInterfaceTestSchema()
+	sch.RegType(&ifaceTestShadowing{}).SetNonNode().ResolvedField("title", "Title", nil).SimpleFields()
+	if err := sch.Validate(); err != nil {
+		t.Errorf("field of the interface method reported: %v", err)
+	}
+}
+
+// Exported like IfaceTestDocument, so the fields embedding them are exported and followed.
+type IfaceTestNamed interface {
+	GetLabel() string
+}
+
+type IfaceTestDocument struct {
+	Name string `json:"name"`
+}
+
+func (d IfaceTestDocument) GetLabel() string {
+	return "document " + d.Name
+}
+
+// Embeds a registered type, resolves to it.
+type ifaceTestDraft struct {
+	*IfaceTestDocument
+	Notes string
+}
+
+// Wraps a value in an embedded interface, resolves to the type of the value.
+type ifaceTestWrapper struct {
+	IfaceTestNamed
+}
+
+type ifaceTestIdentityRoot struct {
+	named []IfaceTestNamed
+}
+
+func (r *ifaceTestIdentityRoot) GetNamed() []IfaceTestNamed {
+	return r.named
+}
+
+func TestTypeIdentity(t *testing.T) {
+	// same Go type name as the package level type, told apart by type identity
+	type IfaceTestDocument struct {
+		IfaceTestNamed
+		Title string `json:"title"`
+	}
+	root := &ifaceTestIdentityRoot{named: []IfaceTestNamed{
+		&IfaceTestDocument{Title: "local"},
+		newIfaceTestDocument("pointer"),
+		*newIfaceTestDocument("value"),
+		&ifaceTestDraft{IfaceTestDocument: newIfaceTestDocument("embedded"), Notes: "draft"},
+		ifaceTestWrapper{newIfaceTestDocument("wrapped")},
+		&ifaceTestWrapper{&IfaceTestDocument{Title: "wrapped local"}},
+		ifaceTestWrapper{&ifaceTestUnregisteredNamed{}},
+	}}
+	sch := NewSchemaInfo()
+	sch.RegType(root).SetRoot().ResolvedFields()
+	sch.RegInterface((*IfaceTestNamed)(nil))
+	sch.RegType(&IfaceTestDocument{}).SetName("ifaceTestLocalDocument").SetNonNode().SimpleFields()
+	sch.RegType(newIfaceTestDocument("")).SetNonNode().SimpleFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := `{ named { __typename ... on IfaceTestDocument { name } ... on ifaceTestLocalDocument { title } } }`
+	want := `{"data":{"named":[` +
+		`{"__typename":"ifaceTestLocalDocument","title":"local"},` +
+		`{"__typename":"IfaceTestDocument","name":"pointer"},` +
+		`{"__typename":"IfaceTestDocument","name":"value"},` +
+		`{"__typename":"IfaceTestDocument","name":"embedded"},` +
+		`{"__typename":"IfaceTestDocument","name":"wrapped"},` +
+		`{"__typename":"ifaceTestLocalDocument","title":"wrapped local"},` +
+		`null]},"errors":[{"message":"cannot resolve the GraphQL type of gographer.ifaceTestWrapper, register it with RegType","locations":[{"line":1,"column":3}],"path":["named",6]}]}`
+	if got := resultJSON(t, schema, query, nil); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func newIfaceTestDocument(name string) *IfaceTestDocument {
+	return &IfaceTestDocument{Name: name}
+}
+
+type ifaceTestUnregisteredNamed struct{}
+
+func (u *ifaceTestUnregisteredNamed) GetLabel() string {
+	return ""
+}
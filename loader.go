@@ -0,0 +1,226 @@
+package gographer
+
+import (
+	"fmt"
+	"golang.org/x/net/context"
+	"reflect"
+	"runtime/debug"
+	"sync"
+)
+
+// BatchFunc loads the values of keys in one call, e.g. with one database query.
+// Values are returned in the order of the keys, nil for keys without value.
+type BatchFunc func(ctx context.Context, keys []string) ([]interface{}, error)
+
+// Loader batches and caches lookups of one kind within a request, like the dataloader of graphql-js.
+// Create it once, e.g. as package variable, resolvers call Load and return the thunk:
+//
+//	var userLoader = gographer.NewLoader(loadUsers)
+//
+//	func (t *Todo) GetOwner(ctx context.Context) func() (*User, error) {
+//		load := userLoader.Load(ctx, t.OwnerID)
+//		return func() (*User, error) {
+//			user, err := load()
+//			owner, _ := user.(*User)
+//			return owner, err
+//		}
+//	}
+//
+// Or register the method with LoaderField and return the thunk as is.
+// Keys loaded by sibling fields are collected while the query executes, the first thunk called
+// loads all of them with one call of the batch func. Batches and the cache are kept in the
+// request context, see WithLoaders, without it every Load calls the batch func on its own.
+type Loader struct {
+	batch BatchFunc
+}
+
+func NewLoader(batch BatchFunc) *Loader {
+	return &Loader{batch: batch}
+}
+
+type loadersKey struct{}
+
+// Per-request state of the loaders.
+type loaderRegistry struct {
+	mutex  sync.Mutex
+	states map[*Loader]*loaderState
+}
+
+type loaderState struct {
+	cache map[string]*loadResult
+	batch *loadBatch // collecting keys, nil until the next Load
+}
+
+// Keys loaded with one call of the batch func.
+type loadBatch struct {
+	keys []string
+	once sync.Once
+}
+
+type loadResult struct {
+	value interface{}
+	err   error
+	batch *loadBatch // nil when loaded or primed
+}
+
+// WithLoaders returns a context keeping the batches and caches of loaders, and of batch ID resolvers,
+// for one request. NewHandler and the command line tool do this, call it for each request when
+// executing queries with graphql.Do.
+func WithLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaderRegistry{states: make(map[*Loader]*loaderState)})
+}
+
+// Load returns a thunk with the value of key, executing the query calls it when the value is needed.
+// Keys loaded before the thunk is called are loaded with the same batch.
+func (l *Loader) Load(ctx context.Context, key string) func() (interface{}, error) {
+	registry, state := l.state(ctx)
+	registry.mutex.Lock()
+	result, ok := state.cache[key]
+	if !ok {
+		if state.batch == nil {
+			state.batch = &loadBatch{}
+		}
+		state.batch.keys = append(state.batch.keys, key)
+		result = &loadResult{batch: state.batch}
+		state.cache[key] = result
+	}
+	batch := result.batch
+	registry.mutex.Unlock()
+	return func() (interface{}, error) {
+		if batch != nil {
+			batch.once.Do(func() { l.dispatch(ctx, registry, state, batch) })
+		}
+		registry.mutex.Lock()
+		defer registry.mutex.Unlock()
+		return result.value, result.err
+	}
+}
+
+// LoadMany returns a func with the values of keys, in the order of the keys, loaded like Load.
+func (l *Loader) LoadMany(ctx context.Context, keys []string) func() ([]interface{}, error) {
+	thunks := make([]func() (interface{}, error), len(keys))
+	for i, key := range keys {
+		thunks[i] = l.Load(ctx, key)
+	}
+	return func() ([]interface{}, error) {
+		values := make([]interface{}, len(thunks))
+		for i, thunk := range thunks {
+			value, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+}
+
+// Prime stores the value of key in the request's cache, e.g. after loading it another way.
+func (l *Loader) Prime(ctx context.Context, key string, value interface{}) {
+	registry, state := l.state(ctx)
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, ok := state.cache[key]; !ok {
+		state.cache[key] = &loadResult{value: value}
+	}
+}
+
+// Request state of the loader, a state for this call only if the context has no loaders.
+func (l *Loader) state(ctx context.Context) (*loaderRegistry, *loaderState) {
+	var registry *loaderRegistry
+	if ctx != nil {
+		registry, _ = ctx.Value(loadersKey{}).(*loaderRegistry)
+	}
+	if registry == nil {
+		registry = &loaderRegistry{states: make(map[*Loader]*loaderState)}
+	}
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	state, ok := registry.states[l]
+	if !ok {
+		state = &loaderState{cache: make(map[string]*loadResult)}
+		registry.states[l] = state
+	}
+	return registry, state
+}
+
+// Load the keys of the batch with one call of the batch func, later keys start a new batch.
+func (l *Loader) dispatch(ctx context.Context, registry *loaderRegistry, state *loaderState, batch *loadBatch) {
+	registry.mutex.Lock()
+	if state.batch == batch {
+		state.batch = nil
+	}
+	keys := batch.keys
+	registry.mutex.Unlock()
+
+	values, err := l.callBatch(ctx, keys)
+	if err == nil && len(values) != len(keys) {
+		err = fmt.Errorf("batch func returned %d values for %d keys", len(values), len(keys))
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for i, key := range keys {
+		result := state.cache[key]
+		result.batch = nil
+		if err != nil {
+			result.err = err
+		} else {
+			result.value = values[i]
+		}
+	}
+}
+
+func (l *Loader) callBatch(ctx context.Context, keys []string) (values []interface{}, err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			values, err = nil, &batchPanic{recovered: e, stack: debug.Stack()}
+		}
+	}()
+	return l.batch(ctx, keys)
+}
+
+// Error of the keys of a batch whose batch func panicked. Fields of the schema report it with the
+// panic handler, see SchemaInfo.fieldError, the first field reporting it gives the error of all keys.
+type batchPanic struct {
+	recovered interface{}
+	stack     []byte
+	once      sync.Once
+	err       error // reported error
+}
+
+func (e *batchPanic) Error() string {
+	return fmt.Sprintf("batch func panicked: %v", e.recovered)
+}
+
+func (e *batchPanic) report(reportPanic func() error) error {
+	e.once.Do(func() { e.err = reportPanic() })
+	return e.err
+}
+
+// Thunk type of deferred resolver results, see LoaderField.
+var thunkType = reflect.TypeOf((func() (interface{}, error))(nil))
+
+// Result type of a deferred resolver result, a func() T or func() (T, error), nil for other types.
+func deferredResultType(typ reflect.Type) reflect.Type {
+	if typ.Kind() != reflect.Func || typ.NumIn() != 0 || numResultOut(typ) != 1 {
+		return nil
+	}
+	return typ.Out(0)
+}
+
+// Thunk calling a deferred resolver result, the executor calls it after the sibling fields are resolved.
+// then is applied to the value, e.g. to build a connection.
+func deferredThunk(deferred reflect.Value, then func(out interface{}) (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		if deferred.IsNil() {
+			return nil, nil
+		}
+		funcType := deferred.Type()
+		outValues := deferred.Call(nil)
+		if err := errorFromOut(funcType, outValues); err != nil {
+			return nil, err
+		}
+		return then(outValues[0].Interface())
+	}
+}
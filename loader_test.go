@@ -0,0 +1,306 @@
+package gographer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/net/context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type loaderTestUser struct {
+	Name string `json:"name"`
+}
+
+type loaderTestRoot struct {
+	users *Loader
+}
+
+func (r *loaderTestRoot) GetFailing(ctx context.Context) func() (*loaderTestUser, error) {
+	return func() (*loaderTestUser, error) {
+		return nil, &FieldError{Err: errors.New("forbidden"), Code: "FORBIDDEN"}
+	}
+}
+
+func (r *loaderTestRoot) GetPanicking(ctx context.Context) func() (*loaderTestUser, error) {
+	return func() (*loaderTestUser, error) {
+		panic("secret thunk panic")
+	}
+}
+
+func (r *loaderTestRoot) GetOwner(ctx context.Context) func() (interface{}, error) {
+	return r.users.Load(ctx, "1")
+}
+
+func (r *loaderTestRoot) GetTodos() []*loaderTestTodo {
+	return []*loaderTestTodo{{"1", r.users}, {"2", r.users}, {"1", r.users}, {"3", r.users}}
+}
+
+type loaderTestTodo struct {
+	OwnerID string `json:"ownerID"`
+	users   *Loader
+}
+
+func (todo *loaderTestTodo) GetOwner(ctx context.Context) func() (interface{}, error) {
+	return todo.users.Load(ctx, todo.OwnerID)
+}
+
+func newLoaderTestSchema(t *testing.T, batch BatchFunc, panics *[]*PanicInfo) graphql.Schema {
+	sch := NewSchemaInfo().SetStrict(true)
+	sch.SetPanicHandler(func(ctx context.Context, info *PanicInfo) error {
+		*panics = append(*panics, info)
+		return nil
+	})
+	sch.RegType(&loaderTestRoot{users: NewLoader(batch)}).SetRoot().
+		ResolvedField("failing", "GetFailing", nil).
+		ResolvedField("panicking", "GetPanicking", nil).
+		LoaderField("owner", "GetOwner", &loaderTestUser{}, nil).
+		ResolvedField("todos", "GetTodos", nil)
+	sch.RegType(&loaderTestTodo{}).SimpleFields().LoaderField("owner", "GetOwner", &loaderTestUser{}, nil)
+	sch.RegType(&loaderTestUser{}).SimpleFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestDeferredFieldErrorCodes(t *testing.T) {
+	var panics []*PanicInfo
+	schema := newLoaderTestSchema(t, func(ctx context.Context, keys []string) ([]interface{}, error) {
+		panic("secret batch panic")
+	}, &panics)
+
+	tests := []struct {
+		query   string
+		message string
+		code    string
+	}{
+		{`{ failing { name } }`, "forbidden", "FORBIDDEN"},
+		{`{ panicking { name } }`, DefaultPanicMessage, ErrorCode_Internal},
+		{`{ owner { name } }`, DefaultPanicMessage, ErrorCode_Internal},
+	}
+	for _, test := range tests {
+		panics = nil
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: test.query, Context: WithLoaders(context.Background())})
+		if len(result.Errors) != 1 {
+			t.Fatalf("%s: got errors %v", test.query, result.Errors)
+		}
+		resultErr := result.Errors[0]
+		if resultErr.Message != test.message || resultErr.Extensions["code"] != test.code {
+			t.Errorf("%s: got %q with extensions %v, want %q with code %s", test.query, resultErr.Message, resultErr.Extensions, test.message, test.code)
+		}
+		if len(resultErr.Path) == 0 || len(resultErr.Locations) == 0 {
+			t.Errorf("%s: error has no path or locations: %+v", test.query, resultErr)
+		}
+		if test.code == ErrorCode_Internal && len(panics) != 1 {
+			t.Errorf("%s: panic handler called %d times", test.query, len(panics))
+		}
+	}
+}
+
+// Batch func returning "value of <key>" for each key, it records the keys of every call.
+type loaderTestBatch struct {
+	mutex sync.Mutex
+	calls [][]string
+	err   error
+	extra bool // return one value too many
+}
+
+func (b *loaderTestBatch) load(ctx context.Context, keys []string) ([]interface{}, error) {
+	b.mutex.Lock()
+	b.calls = append(b.calls, keys)
+	b.mutex.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = "value of " + key
+	}
+	if b.extra {
+		values = append(values, nil)
+	}
+	return values, nil
+}
+
+func TestLoader(t *testing.T) {
+	tests := []struct {
+		name        string
+		withLoaders bool
+		prime       map[string]interface{}
+		rounds      [][]string // keys loaded before calling the thunks of the round
+		calls       [][]string // keys of the batch func calls
+	}{
+		{"one batch", true, nil, [][]string{{"a", "b", "a"}}, [][]string{{"a", "b"}}},
+		{"cached", true, nil, [][]string{{"a", "b"}, {"b", "a"}}, [][]string{{"a", "b"}}},
+		{"next batch", true, nil, [][]string{{"a"}, {"a", "b", "c"}}, [][]string{{"a"}, {"b", "c"}}},
+		{"primed", true, map[string]interface{}{"a": "primed a"}, [][]string{{"a", "b"}}, [][]string{{"b"}}},
+		{"all primed", true, map[string]interface{}{"a": "primed a"}, [][]string{{"a"}}, nil},
+		{"without loaders", false, nil, [][]string{{"a", "b", "a"}}, [][]string{{"a"}, {"b"}, {"a"}}},
+	}
+	for _, test := range tests {
+		batch := &loaderTestBatch{}
+		loader := NewLoader(batch.load)
+		ctx := context.Background()
+		if test.withLoaders {
+			ctx = WithLoaders(ctx)
+		}
+		for key, value := range test.prime {
+			loader.Prime(ctx, key, value)
+		}
+		for _, keys := range test.rounds {
+			thunks := make([]func() (interface{}, error), len(keys))
+			for i, key := range keys {
+				thunks[i] = loader.Load(ctx, key)
+			}
+			for i, thunk := range thunks {
+				want, ok := test.prime[keys[i]]
+				if !ok || !test.withLoaders {
+					want = "value of " + keys[i]
+				}
+				if value, err := thunk(); value != want || err != nil {
+					t.Errorf("%s: %s got %v, %v, want %v", test.name, keys[i], value, err, want)
+				}
+			}
+		}
+		if !reflect.DeepEqual(batch.calls, test.calls) {
+			t.Errorf("%s: got batch calls %v, want %v", test.name, batch.calls, test.calls)
+		}
+	}
+}
+
+func TestLoaderPrimeKeepsLoaded(t *testing.T) {
+	batch := &loaderTestBatch{}
+	loader := NewLoader(batch.load)
+	ctx := WithLoaders(context.Background())
+	load := loader.Load(ctx, "a")
+	loader.Prime(ctx, "a", "primed a")
+	if value, _ := load(); value != "value of a" {
+		t.Errorf("got %v, primed value replaced a pending load", value)
+	}
+	if value, _ := loader.Load(ctx, "a")(); value != "value of a" {
+		t.Errorf("got %v, primed value replaced a loaded value", value)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch *loaderTestBatch
+		err   string
+	}{
+		{"batch error", &loaderTestBatch{err: errors.New("db down")}, "db down"},
+		{"value count", &loaderTestBatch{extra: true}, "batch func returned 3 values for 2 keys"},
+	}
+	for _, test := range tests {
+		loader := NewLoader(test.batch.load)
+		ctx := WithLoaders(context.Background())
+		loadA, loadB := loader.Load(ctx, "a"), loader.Load(ctx, "b")
+		for _, load := range []func() (interface{}, error){loadA, loadB} {
+			if value, err := load(); value != nil || err == nil || err.Error() != test.err {
+				t.Errorf("%s: got %v, %v, want error %q", test.name, value, err, test.err)
+			}
+		}
+		if values, err := loader.LoadMany(ctx, []string{"a", "c"})(); values != nil || err == nil || err.Error() != test.err {
+			t.Errorf("%s: LoadMany got %v, %v, want error %q", test.name, values, err, test.err)
+		}
+	}
+	loader := NewLoader(func(ctx context.Context, keys []string) ([]interface{}, error) {
+		panic("batch panic")
+	})
+	if _, err := loader.Load(WithLoaders(context.Background()), "a")(); err == nil {
+		t.Error("panicking batch func gave no error")
+	} else if _, ok := err.(*batchPanic); !ok {
+		t.Errorf("got %T, want *batchPanic", err)
+	}
+}
+
+func TestLoaderLoadMany(t *testing.T) {
+	batch := &loaderTestBatch{}
+	loader := NewLoader(batch.load)
+	ctx := WithLoaders(context.Background())
+	loadMany := loader.LoadMany(ctx, []string{"c", "a", "c"})
+	load := loader.Load(ctx, "b")
+	values, err := loadMany()
+	want := []interface{}{"value of c", "value of a", "value of c"}
+	if err != nil || !reflect.DeepEqual(values, want) {
+		t.Errorf("got %v, %v, want %v", values, err, want)
+	}
+	if value, _ := load(); value != "value of b" || len(batch.calls) != 1 {
+		t.Errorf("got %v with batch calls %v, want one batch", value, batch.calls)
+	}
+}
+
+func TestLoaderConcurrent(t *testing.T) {
+	batch := &loaderTestBatch{}
+	loader := NewLoader(batch.load)
+	ctx := WithLoaders(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			load := loader.Load(ctx, key)
+			if value, err := load(); value != "value of "+key || err != nil {
+				t.Errorf("%s: got %v, %v", key, value, err)
+			}
+		}(fmt.Sprint(i % 10))
+	}
+	wg.Wait()
+	loaded := make(map[string]int)
+	for _, keys := range batch.calls {
+		for _, key := range keys {
+			loaded[key]++
+		}
+	}
+	for key, n := range loaded {
+		if n != 1 {
+			t.Errorf("%s loaded %d times", key, n)
+		}
+	}
+	if len(loaded) != 10 {
+		t.Errorf("loaded %d keys, want 10", len(loaded))
+	}
+}
+
+func TestLoaderFieldBatching(t *testing.T) {
+	batch := &loaderTestBatch{}
+	schema := newLoaderTestSchema(t, func(ctx context.Context, keys []string) ([]interface{}, error) {
+		batch.load(ctx, keys)
+		users := make([]interface{}, len(keys))
+		for i, key := range keys {
+			users[i] = &loaderTestUser{Name: "user " + key}
+		}
+		return users, nil
+	}, new([]*PanicInfo))
+	tests := []struct {
+		withLoaders bool
+		calls       [][]string
+	}{
+		{true, [][]string{{"1", "2", "3"}}},
+		{false, [][]string{{"1"}, {"2"}, {"1"}, {"3"}}},
+	}
+	for _, test := range tests {
+		batch.calls = nil
+		ctx := context.Background()
+		if test.withLoaders {
+			ctx = WithLoaders(ctx)
+		}
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ todos { owner { name } } }`, Context: ctx})
+		if len(result.Errors) > 0 {
+			t.Fatal(result.Errors)
+		}
+		todos := result.Data.(map[string]interface{})["todos"].([]interface{})
+		for i, ownerID := range []string{"1", "2", "1", "3"} {
+			if owner := todos[i].(map[string]interface{})["owner"]; !reflect.DeepEqual(owner, map[string]interface{}{"name": "user " + ownerID}) {
+				t.Errorf("todo %d: got owner %v, want user %s", i, owner, ownerID)
+			}
+		}
+		if !reflect.DeepEqual(batch.calls, test.calls) {
+			t.Errorf("with loaders %v: got batch calls %v, want %v", test.withLoaders, batch.calls, test.calls)
+		}
+	}
+}
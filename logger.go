@@ -0,0 +1,96 @@
+package gographer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+type LogLevel int
+
+const (
+	LogLevel_Debug LogLevel = iota
+	LogLevel_Info
+	LogLevel_Warn
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevel_Debug:
+		return "DEBUG"
+	case LogLevel_Info:
+		return "INFO"
+	case LogLevel_Warn:
+		return "WARN"
+	default:
+		return fmt.Sprint("LEVEL(", int(level), ")")
+	}
+}
+
+// Logger receives gographer's diagnostic output, keyvals are alternating keys and values.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, keyvals ...interface{}) {}
+func (nopLogger) Info(msg string, keyvals ...interface{})  {}
+func (nopLogger) Warn(msg string, keyvals ...interface{})  {}
+
+// NopLogger discards everything, it's the default logger of SchemaInfo.
+var NopLogger Logger = nopLogger{}
+
+type stdLogger struct {
+	out      *log.Logger
+	minLevel LogLevel
+}
+
+// NewStdLogger writes messages at or above minLevel to a standard library logger,
+// formatted as "LEVEL msg key=value ...".
+func NewStdLogger(out *log.Logger, minLevel LogLevel) Logger {
+	return &stdLogger{out: out, minLevel: minLevel}
+}
+
+func (l *stdLogger) Debug(msg string, keyvals ...interface{}) {
+	l.write(LogLevel_Debug, msg, keyvals)
+}
+
+func (l *stdLogger) Info(msg string, keyvals ...interface{}) {
+	l.write(LogLevel_Info, msg, keyvals)
+}
+
+func (l *stdLogger) Warn(msg string, keyvals ...interface{}) {
+	l.write(LogLevel_Warn, msg, keyvals)
+}
+
+func (l *stdLogger) write(level LogLevel, msg string, keyvals []interface{}) {
+	if level < l.minLevel {
+		return
+	}
+	var buf bytes.Buffer
+	buf.WriteString(level.String())
+	buf.WriteString(" ")
+	buf.WriteString(msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		var val interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			val = keyvals[i+1]
+		}
+		fmt.Fprintf(&buf, " %v=%v", keyvals[i], val)
+	}
+	l.out.Print(buf.String())
+}
+
+var defaultWarningLogger = NewStdLogger(log.New(os.Stderr, "[Gographer] ", log.LstdFlags), LogLevel_Warn)
+
+// Warning writes its arguments as a warning to standard error.
+//
+// Deprecated: set a Logger with SchemaInfo.SetLogger instead.
+func Warning(a ...interface{}) {
+	defaultWarningLogger.Warn(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
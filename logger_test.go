@@ -0,0 +1,104 @@
+package gographer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/net/context"
+	"log"
+	"strings"
+	"testing"
+)
+
+type loggerTestRoot struct{}
+
+func (r *loggerTestRoot) GetCalm() string {
+	return "calm"
+}
+
+func (r *loggerTestRoot) GetPanicking() string {
+	panic("resolver panic")
+}
+
+type loggerTestMutation struct{}
+
+type loggerTestInput struct {
+	Name string `json:"name"`
+}
+
+type loggerTestPayload struct {
+	Name string `json:"name"`
+}
+
+func (m *loggerTestMutation) Explode(in loggerTestInput) (*loggerTestPayload, error) {
+	panic("mutation panic")
+}
+
+func newLoggerTestSchema(t *testing.T, handler PanicHandler, logger Logger) graphql.Schema {
+	sch := NewSchemaInfo().SetLogger(logger).SetPanicHandler(handler)
+	sch.RegType(&loggerTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&loggerTestMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestPanicHandler(t *testing.T) {
+	var panics []*PanicInfo
+	handler := func(ctx context.Context, info *PanicInfo) error {
+		panics = append(panics, info)
+		if info.FieldName == "explode" {
+			return nil // clients get DefaultPanicMessage
+		}
+		return errors.New("reported " + info.Recovered.(string))
+	}
+	schema := newLoggerTestSchema(t, handler, nil)
+	tests := []struct {
+		query     string
+		data      string
+		message   string
+		fieldName string
+	}{
+		{`{ calm panicking }`, `{"calm":"calm","panicking":null}`, "reported resolver panic", "panicking"},
+		{`mutation { explode(input: {name: "x"}) { name } }`, `{"explode":null}`, DefaultPanicMessage, "explode"},
+	}
+	for _, test := range tests {
+		panics = nil
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: test.query})
+		if data, _ := json.Marshal(result.Data); string(data) != test.data {
+			t.Errorf("%s: got data %s, want %s", test.query, data, test.data)
+		}
+		if len(result.Errors) != 1 || result.Errors[0].Message != test.message || result.Errors[0].Extensions["code"] != ErrorCode_Internal {
+			t.Errorf("%s: got errors %v, want %q with code %s", test.query, result.Errors, test.message, ErrorCode_Internal)
+		}
+		if len(panics) != 1 || panics[0].FieldName != test.fieldName || len(panics[0].Stack) == 0 {
+			t.Errorf("%s: panic handler got %v", test.query, panics)
+		}
+	}
+}
+
+func TestPanicWithoutHandler(t *testing.T) {
+	var buf bytes.Buffer
+	schema := newLoggerTestSchema(t, nil, NewStdLogger(log.New(&buf, "", 0), LogLevel_Warn))
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ panicking }`})
+	if len(result.Errors) != 1 || result.Errors[0].Message != DefaultPanicMessage {
+		t.Errorf("got errors %v, want %q", result.Errors, DefaultPanicMessage)
+	}
+	if logged := buf.String(); !strings.HasPrefix(logged, "WARN Recovered panic type=loggerTestRoot field=panicking panic=resolver panic stack=") {
+		t.Errorf("panic not logged, got %q", logged)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewStdLogger(log.New(&buf, "", 0), LogLevel_Info)
+	logger.Debug("hidden", "a", 1)
+	logger.Info("shown", "a", 1, "b")
+	logger.Warn("warned")
+	if want := "INFO shown a=1 b=(MISSING)\nWARN warned\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
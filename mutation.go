@@ -19,21 +19,15 @@ func (sch *SchemaInfo) processMutationType(
 		return nil
 	}
 
-	refType := typ.Type
-	refPtrType := reflect.PtrTo(refType)
-
 	var mutationFields = make(graphql.Fields)
 
 	for _, mf := range typ.mutationFields {
 
-		// try find method for pointer type first
-		var method reflect.Method
-		foundMethod := false
-		if method, foundMethod = refPtrType.MethodByName(mf.MethodName); !foundMethod {
-			method, foundMethod = refType.MethodByName(mf.MethodName)
+		if _, defined := mutationFields[mf.Name]; defined || sch.invalidFields[typ.Name+"."+mf.Name] {
+			continue // reported by validation
 		}
 
-		if foundMethod {
+		if method, foundMethod := typ.findMethod(mf.MethodName); foundMethod {
 
 			funcType := method.Func.Type()
 			mutConf := relay.MutationConfig{}
@@ -41,38 +35,33 @@ func (sch *SchemaInfo) processMutationType(
 
 			var inputFields = make(graphql.InputObjectConfigFieldMap)
 
+			argIndex := firstArgIndex(funcType) // skip receiver and optional context
+
 			if mf.AutoArgs {
 				// use struct args
-				if funcType.NumIn() == 2 {
-					argStructType := funcType.In(1)
+				if funcType.NumIn() == argIndex+1 {
+					argStructType := funcType.In(argIndex)
 					if argStructType.Kind() == reflect.Struct {
 						for i := 0; i < argStructType.NumField(); i++ {
 
 							argField := argStructType.Field(i)
-							argFieldName := lowerFirst(argField.Name)
-							argQLType := ToQLType(argField.Type)
-
-							var defaultValue interface{} = nil
-							if defTag := argField.Tag.Get(TAG_DefaultValue); defTag != "" {
-								defaultValue = ParseString(defTag, argField.Type)
-							}
-							if nonNullTag := argField.Tag.Get(TAG_NonNull); nonNullTag == "true" {
-								argQLType = graphql.NewNonNull(argQLType)
+							argFieldName, ok := sch.fieldName(argField)
+							if !ok {
+								continue // unexported or excluded by its tag
 							}
+							argQLType, defaultValue := sch.inputFieldQLType(argField) // type, nonNull, gqlid and def tags
 							inputFields[argFieldName] = &graphql.InputObjectFieldConfig{
 								Type:         argQLType,
 								DefaultValue: defaultValue,
 							}
 						}
-					} else {
-						Warning("AutoArgs needs a struct value as argument", mf.MethodName, argStructType, argStructType.Kind())
 					}
 				}
 
 			} else {
-				for i := 1; i < funcType.NumIn(); i++ {
-					argQLType := ToQLType(funcType.In(i)) // TODO: handle GraphQL ID type?
-					arg := mf.Args[i-1]
+				for i := argIndex; i < funcType.NumIn(); i++ {
+					argQLType := sch.toQLInputType(funcType.In(i)) // plain args have no ID type, see ArgInfo
+					arg := mf.Args[i-argIndex]
 					if arg.NonNull {
 						argQLType = graphql.NewNonNull(argQLType)
 					}
@@ -90,7 +79,9 @@ func (sch *SchemaInfo) processMutationType(
 			var outQLTypes []graphql.Output
 			var outputInfos []OutputInfo
 
-			if mf.AutoOutputs {
+			if mf.AutoOutputs && numResultOut(funcType) == 0 {
+				// method only returns an error, payload has no output fields
+			} else if mf.AutoOutputs {
 
 				// use struct args to infer output field types
 				outStructType := funcType.Out(0)
@@ -102,47 +93,39 @@ func (sch *SchemaInfo) processMutationType(
 					for i := 0; i < outStructType.NumField(); i++ {
 
 						outField := outStructType.Field(i)
-						outFieldName := lowerFirst(outField.Name)
-						outQLType, qlTypeKind := getComplexQLType(outField.Type, outField.Name, qlTypes, qlConns) // use full name to infer type
-
-						var qlFieldName string
-						if jsonTag := outField.Tag.Get("json"); jsonTag != "" {
-							qlFieldName = jsonTag
-						} else {
-							qlFieldName = outFieldName
+						outFieldName, ok := sch.fieldName(outField)
+						if !ok {
+							continue // unexported or excluded by its tag
 						}
+						isConnection := outField.Tag.Get(TAG_Connection) == "true" || strings.HasSuffix(outField.Name, "Connection")
+						elemTypeName := outField.Tag.Get(TAG_ElemType)
+						outQLType, qlTypeKind := sch.getComplexQLType(outField.Type, outField.Name, elemTypeName, isConnection, qlTypes, qlConns) // full name infers a missing elemType
 
 						outInfo := OutputInfo{
-							Name: qlFieldName,
+							Name:         outFieldName,
+							IsConnection: qlTypeKind == QLTypeKind_Connection,
+							goIndex:      i,
 						}
 
 						if qlTypeKind == QLTypeKind_Edge {
-							if strings.HasSuffix(outField.Name, "Edge") {
-								outInfo.ElemTypeName = strings.TrimSuffix(outField.Name, "Edge")
-							} else {
-								Warning("Invalid field name for EdgeType, need to specify struct type", outField.Name)
-							}
+							outInfo.ElemTypeName = strings.TrimSuffix(outQLType.Name(), "Edge") // named after the node type
 						} else if qlTypeKind == QLTypeKind_Connection {
-							if strings.HasSuffix(outField.Name, "Connection") {
-								outInfo.ElemTypeName = strings.TrimSuffix(outField.Name, "Connection")
-							} else {
-								Warning("Invalid field name for ConnectionType, need to specify struct type", outField.Name)
-							}
+							outInfo.ElemTypeName = strings.TrimSuffix(outQLType.Name(), "Connection")
 						}
 
 						outQLTypes = append(outQLTypes, outQLType)
 						outputInfos = append(outputInfos, outInfo)
 					}
 					mf.Outputs = outputInfos // save information for dynamicCallMutateAndGetPayload
-				} else {
-					Warning("AutoOutputs needs a struct value as argument", mf.MethodName, outStructType, outStructType.Kind())
 				}
 
 			} else {
 				// use manually OutputInfo and function type's output information
-				for i := 0; i < funcType.NumOut(); i++ {
+				for i := 0; i < numResultOut(funcType); i++ { // trailing error is not an output field
 					outputInfo := mf.Outputs[i]
-					outQLType, _ := getComplexQLType(funcType.Out(i), outputInfo.Name, qlTypes, qlConns)
+					elemTypeName := sch.nodeTypeName(outputInfo.ElemTypeName, outputInfo.ElemInterface)
+					outQLType, qlTypeKind := sch.getComplexQLType(funcType.Out(i), outputInfo.Name, elemTypeName, outputInfo.IsConnection, qlTypes, qlConns)
+					outputInfo.IsConnection = qlTypeKind == QLTypeKind_Connection
 					outQLTypes = append(outQLTypes, outQLType)
 					outputInfos = append(outputInfos, outputInfo)
 				}
@@ -153,30 +136,38 @@ func (sch *SchemaInfo) processMutationType(
 				outInfo := outputInfos[i]
 				outQLType := outQLTypes[i]
 
-				outputFields[outInfo.Name] = &graphql.Field{
+				outField := &graphql.Field{
 					Type: outQLType,
 					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 						payload := p.Source.(map[string]interface{})
 						output := payload[outInfo.Name]
-						fmt.Println("Got output payload", outInfo.Name, output)
+						if outInfo.IsConnection && output != nil {
+							return resolveConnection(p.Context, output, p.Args)
+						}
 						return output, nil
 					},
 				}
+				if outInfo.IsConnection {
+					outField.Args = relay.NewConnectionArgs(graphql.FieldConfigArgument{})
+				}
+				outputFields[outInfo.Name] = outField
 
 			}
 			mutConf.OutputFields = outputFields
 
 			mfCaptured := mf
 			mutConf.MutateAndGetPayload = func(inputMap map[string]interface{}, info graphql.ResolveInfo, ctx context.Context) (map[string]interface{}, error) {
-				return sch.dynamicCallMutateAndGetPayload(mfCaptured, funcType, typ, inputFields, inputMap)
+				return sch.dynamicCallMutateAndGetPayload(mfCaptured, funcType, typ, inputFields, inputMap, info, ctx)
 			}
 
 			mutationFields[mf.Name] = relay.MutationWithClientMutationID(mutConf)
-		} else {
-			Warning("Cannot find method", mf.MethodName, "for type", refType.Name())
 		}
 	}
 
+	if len(mutationFields) == 0 {
+		return nil // all mutation fields are invalid
+	}
+
 	mutationType := graphql.NewObject(graphql.ObjectConfig{
 		Name:   "Mutation",
 		Fields: mutationFields,
@@ -189,33 +180,52 @@ func (sch *SchemaInfo) dynamicCallMutateAndGetPayload(
 	funcType reflect.Type,
 	typ *TypeInfo,
 	inputFields graphql.InputObjectConfigFieldMap,
-	inputMap map[string]interface{}) (map[string]interface{}, error) {
+	inputMap map[string]interface{},
+	info graphql.ResolveInfo,
+	ctx context.Context) (outMap map[string]interface{}, err error) {
 
-	fmt.Println("[dynamicCallMutateAndGetPayload]", "funcType=", funcType, "mf=", mf, "typ=", typ.Name)
+	defer func() {
+		if e := recover(); e != nil {
+			outMap, err = nil, sch.recoverPanic(ctx, typ.Name, mf.Name, e)
+		}
+	}()
+
+	sch.logger.Debug("Calling mutation", "type", typ.Name, "field", mf.Name, "funcType", funcType)
 
 	mutVal := reflect.ValueOf(typ.instance)
 	methodVal := mutVal.MethodByName(mf.MethodName)
 
 	var inValues []reflect.Value
 
+	argIndex := firstArgIndex(funcType)
+	if argIndex == 2 {
+		inValues = append(inValues, contextValue(ctx)) // inject request context
+	}
+
+	given, _ := givenArgs(info, ctx)["input"].(map[string]interface{}) // tells omitted input fields from explicit nulls
+
 	if mf.AutoArgs {
 		// use struct args
-		if funcType.NumIn() == 2 {
-			argStructType := funcType.In(1)
+		if funcType.NumIn() == argIndex+1 {
+			argStructType := funcType.In(argIndex)
 			argStructVal := reflect.New(argStructType).Elem()
 
 			for i := 0; i < argStructVal.NumField(); i++ {
 				argStructField := argStructType.Field(i)
 				argStructFieldVal := argStructVal.Field(i)
-				lowerFirstFieldName := lowerFirst(argStructField.Name)
+				argFieldName, ok := sch.fieldName(argStructField)
+				if !ok {
+					continue
+				}
 
 				var argObj interface{} = nil
 				var hasInput bool
-				if argObj, hasInput = inputMap[lowerFirstFieldName]; !hasInput {
-					argObj = inputFields[lowerFirstFieldName].DefaultValue
+				if argObj, hasInput = inputMap[argFieldName]; !hasInput {
+					argObj = inputFields[argFieldName].DefaultValue
 				}
-				if argObj != nil {
-					argStructFieldVal.Set(reflect.ValueOf(argObj)) // bind field value
+				argGiven, isGiven := given[argFieldName]
+				if err := sch.assignInputField(argStructFieldVal, argStructField, argObj, argGiven, isGiven); err != nil { // bind field value
+					return nil, &FieldError{TypeName: typ.Name, FieldName: mf.Name, Err: fmt.Errorf("input field %s: %v", argFieldName, err)}
 				}
 			}
 			inValues = append(inValues, argStructVal)
@@ -223,28 +233,41 @@ func (sch *SchemaInfo) dynamicCallMutateAndGetPayload(
 
 	} else {
 		// use plain args
-		for _, arg := range mf.Args {
+		for i, arg := range mf.Args {
 			var argObj interface{}
 			var hasInput bool
 			if argObj, hasInput = inputMap[arg.Name]; !hasInput {
 				argObj = arg.DefaultValue
 			}
-			inValues = append(inValues, reflect.ValueOf(argObj))
+			argVal := reflect.New(funcType.In(argIndex + i)).Elem()
+			argGiven, isGiven := given[arg.Name]
+			if err := sch.assignInput(argVal, argObj, argGiven, isGiven); err != nil {
+				return nil, &FieldError{TypeName: typ.Name, FieldName: mf.Name, Err: fmt.Errorf("input field %s: %v", arg.Name, err)}
+			}
+			inValues = append(inValues, argVal)
 		}
 	}
 
 	outValues := methodVal.Call(inValues) // call mutate function!
 
-	outMap := make(map[string]interface{})
+	if err := errorFromOut(funcType, outValues); err != nil {
+		return nil, sch.fieldError(ctx, typ.Name, mf.Name, err)
+	}
 
-		for i, outInfo := range mf.Outputs {
-			// set output fields map, will be sent to output fields resolver
-			if mf.AutoOutputs {
-				outMap[outInfo.Name] = outValues[0].Elem().Field(i).Interface() // extract field value from output struct
-			} else {
-				outMap[outInfo.Name] = outValues[i].Interface()
+	outMap = make(map[string]interface{})
+
+	for i, outInfo := range mf.Outputs {
+		// set output fields map, will be sent to output fields resolver
+		if mf.AutoOutputs {
+			outStructVal := reflect.Indirect(outValues[0])
+			if !outStructVal.IsValid() {
+				break // nil output struct, leave output fields empty
 			}
+			outMap[outInfo.Name] = outStructVal.Field(outInfo.goIndex).Interface() // extract field value from output struct
+		} else {
+			outMap[outInfo.Name] = outValues[i].Interface()
 		}
+	}
 
 	return outMap, nil
 }
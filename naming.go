@@ -0,0 +1,365 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TypeKind is the kind of GraphQL type a NamingStrategy names.
+type TypeKind string
+
+const (
+	TypeKind_Object    TypeKind = "OBJECT"
+	TypeKind_Input     TypeKind = "INPUT_OBJECT"
+	TypeKind_Enum      TypeKind = "ENUM"
+	TypeKind_Interface TypeKind = "INTERFACE"
+	TypeKind_Union     TypeKind = "UNION"
+	TypeKind_Scalar    TypeKind = "SCALAR"
+)
+
+// NamingStrategy returns the GraphQL name of a Go type registered as kind, or used as input object.
+type NamingStrategy func(goType reflect.Type, kind TypeKind) string
+
+// DefaultNaming uses the Go type name, input objects get the suffix Input, e.g. Address becomes AddressInput.
+func DefaultNaming(goType reflect.Type, kind TypeKind) string {
+	name := goType.Name()
+	if kind == TypeKind_Input && !strings.HasSuffix(name, "Input") {
+		name += "Input"
+	}
+	return name
+}
+
+// PackageNaming prefixes the names of DefaultNaming with the Go package name,
+// so types of different packages don't collide, e.g. admin.User becomes AdminUser.
+func PackageNaming(goType reflect.Type, kind TypeKind) string {
+	if goType.PkgPath() == "" {
+		return DefaultNaming(goType, kind)
+	}
+	return upperFirst(path.Base(goType.PkgPath())) + DefaultNaming(goType, kind)
+}
+
+// Set how registered Go types are named, DefaultNaming by default. Should be set before registering types,
+// names set with SetName take precedence. Built-in scalars keep their names.
+func (sch *SchemaInfo) SetNamingStrategy(strategy NamingStrategy) *SchemaInfo {
+	if strategy == nil {
+		strategy = DefaultNaming
+	}
+	sch.naming = strategy
+	return sch
+}
+
+// GraphQL name of a Go type by the naming strategy.
+func (sch *SchemaInfo) typeName(goType reflect.Type, kind TypeKind) string {
+	if sch.naming == nil || goType.Name() == "" {
+		return DefaultNaming(goType, kind)
+	}
+	return sch.naming(goType, kind)
+}
+
+// Index the registered object types named name. A name used by more than one Go type resolves to none of them,
+// so global IDs and node types aren't resolved to the wrong type, the collision is reported by Validate.
+func (sch *SchemaInfo) indexTypeName(name string) {
+	var named *TypeInfo
+	for _, typ := range sch.types {
+		if typ.Name != name {
+			continue
+		}
+		if named != nil && named.Type != typ.Type {
+			delete(sch.typesByName, name)
+			return
+		}
+		if named == nil {
+			named = typ
+		}
+	}
+	if named == nil {
+		delete(sch.typesByName, name)
+	} else {
+		sch.typesByName[name] = named
+	}
+}
+
+// Report GraphQL type names used by more than one registered type, of any kind, or by a registered type and
+// a built-in or generated type, see generatedTypeNames.
+// Input objects are claimed by checkInputType, which reports names shared by two input structs.
+func (sch *SchemaInfo) checkTypeNames() SchemaErrors {
+	type owner struct {
+		kind   string
+		goType reflect.Type // nil for built-in and generated types
+	}
+	owners := make(map[string][]owner)
+	add := func(name string, kind TypeKind, goType reflect.Type) {
+		owners[name] = append(owners[name], owner{strings.ToLower(string(kind)), goType})
+	}
+	for _, typ := range sch.types {
+		if typ.isMutationType {
+			continue // built as the generated Mutation type
+		}
+		add(typ.Name, TypeKind_Object, typ.Type)
+	}
+	for _, enum := range sch.enums {
+		add(enum.Name, TypeKind_Enum, enum.Type)
+	}
+	for _, scalarType := range sch.scalarTypes {
+		add(sch.scalarsByType[scalarType].Name, TypeKind_Scalar, scalarType)
+	}
+	for _, iface := range sch.interfaces {
+		add(iface.Name, TypeKind_Interface, iface.Type)
+	}
+	for _, union := range sch.unions {
+		add(union.Name, TypeKind_Union, union.Type)
+	}
+	for name, input := range sch.inputObjects {
+		add(name, TypeKind_Input, input.Type)
+	}
+	for name, generated := range sch.generatedTypeNames() {
+		if len(owners[name]) > 0 {
+			owners[name] = append(owners[name], owner{kind: generated})
+		}
+	}
+
+	var names []string
+	for name := range owners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs SchemaErrors
+	for _, name := range names {
+		if len(owners[name]) < 2 {
+			continue
+		}
+		var used []string
+		for _, o := range owners[name] {
+			if o.goType == nil {
+				used = append(used, o.kind)
+			} else {
+				used = append(used, fmt.Sprintf("%s %v", o.kind, o.goType))
+			}
+		}
+		msg := "type name is used by " + strings.Join(used, " and ") + ", use SetName or SetNamingStrategy"
+		if len(owners[name]) == 2 && owners[name][0] == owners[name][1] {
+			msg = fmt.Sprintf("%v is registered more than once", owners[name][0].goType)
+		}
+		errs = append(errs, &SchemaError{TypeName: name, Message: msg})
+	}
+	return errs
+}
+
+// Names of the built-in types and of the types built for Relay, connections and mutations,
+// with what they are used for. Connections and edges are named after their node type.
+func (sch *SchemaInfo) generatedTypeNames() map[string]string {
+	names := map[string]string{
+		"Node":     "the Relay Node interface",
+		"PageInfo": "the PageInfo of connections",
+	}
+	for _, scalar := range []*graphql.Scalar{graphql.String, graphql.Int, graphql.Float, graphql.Boolean, graphql.ID} {
+		names[scalar.Name()] = "the built-in scalar " + scalar.Name()
+	}
+	if wideIntType, ok := sch.wideIntQLType().(*graphql.Scalar); ok {
+		names[wideIntType.Name()] = "the wide integer scalar " + wideIntType.Name()
+	}
+	addConnection := func(nodeName string) {
+		if nodeName != "" {
+			names[nodeName+"Connection"] = "the connection of " + nodeName
+			names[nodeName+"Edge"] = "the edge of " + nodeName
+		}
+	}
+
+	for _, typ := range sch.types {
+		if typ.isMutationType {
+			names["Mutation"] = "the mutation type"
+			for _, mf := range typ.mutationFields {
+				names[mf.MethodName+"Input"] = "the input of mutation " + mf.Name
+				names[mf.MethodName+"Payload"] = "the payload of mutation " + mf.Name
+				for _, nodeName := range sch.outputNodeNames(typ, mf) {
+					addConnection(nodeName)
+				}
+			}
+			continue
+		}
+		for _, rf := range typ.resolvedFields {
+			if funcType, ok := typ.resolvedFuncType(rf); ok && numResultOut(funcType) == 1 {
+				addConnection(sch.connectionNodeName(rf.returnGoType(funcType), rf.Name, sch.nodeTypeName("", rf.ElemInterface), rf.IsConnection))
+			}
+		}
+	}
+	for _, iface := range sch.interfaces {
+		if iface.Type.Kind() != reflect.Interface {
+			continue
+		}
+		for _, rf := range iface.fields(sch) {
+			if funcType := iface.methodType(rf.MethodName); numResultOut(funcType) == 1 {
+				addConnection(sch.connectionNodeName(funcType.Out(0), rf.Name, "", rf.IsConnection))
+			}
+		}
+	}
+	return names
+}
+
+// Node types of the connection and edge outputs of a mutation.
+func (sch *SchemaInfo) outputNodeNames(typ *TypeInfo, mf MutationFieldInfo) []string {
+	method, found := typ.findMethod(mf.MethodName)
+	if !found {
+		return nil
+	}
+	funcType := method.Func.Type()
+	var nodeNames []string
+	if mf.AutoOutputs {
+		if numResultOut(funcType) == 0 {
+			return nil
+		}
+		outStructType := funcType.Out(0)
+		if outStructType.Kind() == reflect.Ptr {
+			outStructType = outStructType.Elem()
+		}
+		if outStructType.Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < outStructType.NumField(); i++ {
+			outField := outStructType.Field(i)
+			isConnection := outField.Tag.Get(TAG_Connection) == "true" || strings.HasSuffix(outField.Name, "Connection")
+			nodeNames = append(nodeNames, sch.connectionNodeName(outField.Type, outField.Name, outField.Tag.Get(TAG_ElemType), isConnection))
+		}
+	} else {
+		for i := 0; i < numResultOut(funcType) && i < len(mf.Outputs); i++ {
+			outputInfo := mf.Outputs[i]
+			elemTypeName := sch.nodeTypeName(outputInfo.ElemTypeName, outputInfo.ElemInterface)
+			nodeNames = append(nodeNames, sch.connectionNodeName(funcType.Out(i), outputInfo.Name, elemTypeName, outputInfo.IsConnection))
+		}
+	}
+	return nodeNames
+}
+
+// Node type name of a field whose Go type makes it a connection or edge, see getComplexQLType, empty for other fields.
+func (sch *SchemaInfo) connectionNodeName(goType reflect.Type, fieldName string, elemTypeName string, asConnection bool) string {
+	elemType := goType
+	if goType.Kind() == reflect.Slice || goType.Kind() == reflect.Ptr {
+		elemType = goType.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+	}
+	if elemType == edgeType || elemType == relayConnectionType {
+		if elemTypeName == "" {
+			elemTypeName = inferTypeNameFromField(fieldName)
+		}
+		return elemTypeName
+	}
+	if typ, ok := sch.typesByType[elemType]; ok && asConnection && goType.Kind() == reflect.Slice {
+		return typ.Name
+	}
+	return ""
+}
+
+// FieldCase returns the GraphQL name of a struct field without graphql or json tag name from its Go name.
+type FieldCase func(goName string) string
+
+// CamelCase lowers the leading upper case letters, the last one stays upper case before a lower case letter,
+// e.g. ID becomes id, IDs ids, URLPath urlPath and UserID userID. The default field case.
+func CamelCase(goName string) string {
+	runes := []rune(goName)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && string(runes[i+1:]) != "s" {
+			break // first letter of the next word, plural initialisms like IDs are one word
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// GoCase keeps the Go name, e.g. UserID stays UserID.
+func GoCase(goName string) string {
+	return goName
+}
+
+// SnakeCase separates words with underscores, e.g. UserID becomes user_id, UserIDs user_ids and URLPath url_path.
+func SnakeCase(goName string) string {
+	runes := []rune(goName)
+	var out []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]) && string(runes[i+1:]) != "s"))
+			if startsWord {
+				out = append(out, '_')
+			}
+			r = unicode.ToLower(r)
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// Set how struct fields without graphql or json tag name, and fields of methods, are named, CamelCase by default.
+// Applies to simple, resolved, interface and mutation fields, argument and input structs and mutation outputs,
+// should be set before registering types.
+func (sch *SchemaInfo) SetFieldCase(fieldCase FieldCase) *SchemaInfo {
+	if fieldCase == nil {
+		fieldCase = CamelCase
+	}
+	sch.fieldCase = fieldCase
+	return sch
+}
+
+// GraphQL name of a struct field, false for fields left out of the schema.
+// The name is taken from the TAG_GraphQL tag, the json tag or the field case, in this order,
+// a tag name "-" leaves the field out, like unexported fields. Tag options after the comma are ignored here.
+func (sch *SchemaInfo) fieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	for _, key := range []string{TAG_GraphQL, "json"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _ := parseTag(tag)
+		if name == "-" && tag == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return sch.caseName(field.Name), true
+}
+
+// Field name of a GetXxx method by the field case, e.g. GetURLPath becomes urlPath.
+// GetXxxConnection methods are the connection field xxx, mutation methods keep their whole name.
+func (sch *SchemaInfo) methodFieldName(methodName string) (name string, isConnection bool) {
+	name = strings.TrimPrefix(methodName, "Get")
+	if connName := strings.TrimSuffix(name, "Connection"); connName != name && connName != "" {
+		name, isConnection = connName, true
+	}
+	return sch.caseName(name), isConnection
+}
+
+// Go name in the field case, CamelCase if none is set.
+func (sch *SchemaInfo) caseName(goName string) string {
+	if sch.fieldCase == nil {
+		return CamelCase(goName)
+	}
+	return sch.fieldCase(goName)
+}
+
+// Name and options of a struct tag value like "name,omitempty".
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// Whether the TAG_GraphQL tag of the field has the option, e.g. nonNull in `graphql:"name,nonNull"`.
+func hasTagOption(field reflect.StructField, option string) bool {
+	_, options := parseTag(field.Tag.Get(TAG_GraphQL))
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
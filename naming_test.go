@@ -0,0 +1,214 @@
+package gographer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldCases(t *testing.T) {
+	tests := []struct {
+		goName string
+		camel  string
+		snake  string
+	}{
+		{"ID", "id", "id"},
+		{"IDs", "ids", "ids"},
+		{"UserID", "userID", "user_id"},
+		{"UserIDs", "userIDs", "user_ids"},
+		{"URLPath", "urlPath", "url_path"},
+		{"HTTPServer2Go", "httpServer2Go", "http_server2_go"},
+		{"Name", "name", "name"},
+		{"x", "x", "x"},
+	}
+	for _, test := range tests {
+		if got := CamelCase(test.goName); got != test.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", test.goName, got, test.camel)
+		}
+		if got := SnakeCase(test.goName); got != test.snake {
+			t.Errorf("SnakeCase(%q) = %q, want %q", test.goName, got, test.snake)
+		}
+	}
+}
+
+type namingTestStruct struct {
+	ID       string `json:"id,omitempty"`
+	Secret   string `json:"-"`
+	Title    string `graphql:"heading" json:"title"`
+	Required string `graphql:",nonNull" json:"req"`
+	URLPath  string
+	hidden   string
+}
+
+func TestFieldNames(t *testing.T) {
+	typ := reflect.TypeOf(namingTestStruct{})
+	tests := []struct {
+		fieldCase FieldCase
+		names     []string // of the fields in order, "" if left out
+	}{
+		{nil, []string{"id", "", "heading", "req", "urlPath", ""}},
+		{SnakeCase, []string{"id", "", "heading", "req", "url_path", ""}},
+		{GoCase, []string{"id", "", "heading", "req", "URLPath", ""}},
+	}
+	for _, test := range tests {
+		sch := NewSchemaInfo().SetFieldCase(test.fieldCase)
+		for i, want := range test.names {
+			name, ok := sch.fieldName(typ.Field(i))
+			if name != want || ok != (want != "") {
+				t.Errorf("field %s: got %q, %v, want %q", typ.Field(i).Name, name, ok, want)
+			}
+		}
+	}
+	if !hasTagOption(typ.Field(3), "nonNull") || hasTagOption(typ.Field(0), "nonNull") {
+		t.Error("nonNull option not parsed")
+	}
+}
+
+func TestMethodFieldNames(t *testing.T) {
+	tests := []struct {
+		fieldCase    FieldCase
+		method       string
+		name         string
+		isConnection bool
+	}{
+		{nil, "GetURLPath", "urlPath", false},
+		{nil, "GetID", "id", false},
+		{nil, "GetTodosConnection", "todos", true},
+		{nil, "GetConnection", "connection", false},
+		{nil, "AddTodo", "addTodo", false},
+		{SnakeCase, "GetUserName", "user_name", false},
+		{SnakeCase, "GetTodoItemsConnection", "todo_items", true},
+		{GoCase, "GetName", "Name", false},
+	}
+	for _, test := range tests {
+		sch := NewSchemaInfo().SetFieldCase(test.fieldCase)
+		name, isConnection := sch.methodFieldName(test.method)
+		if name != test.name || isConnection != test.isConnection {
+			t.Errorf("%s: got %q, %v, want %q, %v", test.method, name, isConnection, test.name, test.isConnection)
+		}
+	}
+}
+
+type namingTestMutations struct{}
+
+func (m *namingTestMutations) GetOrCreate() (string, error) {
+	return "", nil
+}
+
+func (m *namingTestMutations) ResetConnection() (string, error) {
+	return "", nil
+}
+
+func TestMutationFieldNames(t *testing.T) {
+	tests := []struct {
+		fieldCase FieldCase
+		names     []string
+	}{
+		{nil, []string{"getOrCreate", "resetConnection"}},
+		{SnakeCase, []string{"get_or_create", "reset_connection"}},
+		{GoCase, []string{"GetOrCreate", "ResetConnection"}},
+	}
+	for _, test := range tests {
+		sch := NewSchemaInfo().SetFieldCase(test.fieldCase)
+		typ := sch.RegType(&namingTestMutations{}).SetMutation().MutationFields()
+		var names []string
+		for _, mf := range typ.mutationFields {
+			names = append(names, mf.Name)
+		}
+		if !reflect.DeepEqual(names, test.names) {
+			t.Errorf("got mutation fields %v, want %v", names, test.names)
+		}
+	}
+}
+
+type namingTestA struct{}
+
+type namingTestB struct{}
+
+func TestTypeNameIndex(t *testing.T) {
+	sch := NewSchemaInfo()
+	a := sch.RegType(&namingTestA{})
+	b := sch.RegType(&namingTestB{}).SetName("namingTestA")
+	if _, ok := sch.typesByName["namingTestA"]; ok {
+		t.Error("colliding name resolves to a type")
+	}
+	if !hasSchemaError(sch.Validate(), "namingTestA", "type name is used by") {
+		t.Error("collision not reported")
+	}
+	b.SetName("Renamed")
+	if sch.typesByName["namingTestA"] != a || sch.typesByName["Renamed"] != b {
+		t.Errorf("names not resolved after SetName: %v", sch.typesByName)
+	}
+}
+
+type namingTestRenamed struct {
+	Name string
+}
+
+func TestErrorsAfterSetName(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&namingTestRenamed{}).SimpleField("missing").SimpleField("Name").SetName("Renamed")
+	err := sch.Validate()
+	if !hasSchemaError(err, "Renamed", "") || hasSchemaError(err, "namingTestRenamed", "") {
+		t.Errorf("errors not reported under the final name: %v", err)
+	}
+	if errs, _ := err.(SchemaErrors); !errs.fieldSet()["Renamed.missing"] {
+		t.Errorf("invalid field not keyed by the final name: %v", err)
+	}
+}
+
+// Whether err is SchemaErrors with an error of the type whose message starts with prefix.
+func hasSchemaError(err error, typeName string, prefix string) bool {
+	errs, _ := err.(SchemaErrors)
+	for _, e := range errs {
+		if e.TypeName == typeName && strings.HasPrefix(e.Message, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+type namingTestRoot struct{}
+
+type namingTestTodo struct {
+	ID string `json:"id"`
+}
+
+type TodoConnection struct{}
+
+type PageInfo struct{}
+
+type Long struct{}
+
+type namingTestMutation struct{}
+
+type AddTodoPayload struct{}
+
+func (r *namingTestRoot) GetTodosConnection() []*namingTestTodo {
+	return nil
+}
+
+func (m *namingTestMutation) AddTodo() (*namingTestTodo, error) {
+	return nil, nil
+}
+
+func TestGeneratedTypeNames(t *testing.T) {
+	sch := NewSchemaInfo().SetWideIntMapping(IntMapping_Long)
+	sch.RegType(&namingTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&namingTestMutation{}).SetMutation().MutationField("addTodo", "AddTodo", nil, []OutputInfo{{Name: "todo"}})
+	sch.RegType(&namingTestTodo{}).SimpleFields().SetName("namingTestTodo")
+	for _, instance := range []interface{}{&TodoConnection{}, &PageInfo{}, &Long{}, &AddTodoPayload{}} {
+		sch.RegType(instance)
+	}
+	sch.RegType(&namingTestA{}).SetName("namingTestTodoConnection")
+	sch.RegType(&namingTestB{}).SetName("namingTestTodoEdge")
+	err := sch.Validate()
+	for _, name := range []string{"namingTestTodoConnection", "namingTestTodoEdge", "PageInfo", "Long", "AddTodoPayload"} {
+		if !hasSchemaError(err, name, "type name is used by") {
+			t.Errorf("collision with generated type %s not reported: %v", name, err)
+		}
+	}
+	if hasSchemaError(err, "TodoConnection", "") || hasSchemaError(err, "Mutation", "") {
+		t.Errorf("names of types that aren't generated reported: %v", err)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/relay"
 	"reflect"
-	"runtime/debug"
 )
 
 func (sch *SchemaInfo) processObjectType(
@@ -27,43 +26,45 @@ func (sch *SchemaInfo) processObjectType(
 		for fieldName, field := range typ.fields {
 			fields[fieldName] = field
 		}
+		for _, sf := range typ.simpleFields {
+			if sch.invalidFields[typ.Name+"."+sf.Name] || (sf.auto && !sch.includeAutoField(typ, sf.Name, sf.GoType)) {
+				continue
+			}
+			fields[sf.Name] = &graphql.Field{
+				Type:    sch.toQLType(sf.GoType),
+				Resolve: typ.simpleFieldResolver(sf.goName),
+			}
+		}
 
-		// node field for root
+		// node and nodes fields for root
 		if typ.isRootType {
 			fields["node"] = nodeDefinitions.NodeField
+			fields["nodes"] = sch.nodesField
 		}
 
-		// resolved fields
-		for _, rf := range typ.resolvedFields {
-
-			refType := typ.Type
-			refPtrType := reflect.PtrTo(refType)
+		// resolved fields, and fields of implemented interfaces not defined explicitly
+		resolvedFields := append(typ.resolvedFields[:len(typ.resolvedFields):len(typ.resolvedFields)], sch.interfaceFields(typ)...)
+		for _, rf := range resolvedFields {
 
-			var funcType reflect.Type
-
-			if rf.ExtensionFunc != nil {
-				funcType = reflect.TypeOf(rf.ExtensionFunc)
-			} else {
-				var method reflect.Method
-				foundMethod := false
-				if method, foundMethod = refPtrType.MethodByName(rf.MethodName); !foundMethod {
-					method, foundMethod = refType.MethodByName(rf.MethodName)
-				}
-				if foundMethod {
-					funcType = method.Func.Type()
-				} else {
-					Warning("Cannot find method", rf.MethodName, "for type", refType.Name())
-					continue
-				}
+			if sch.invalidFields[typ.Name+"."+rf.Name] {
+				continue // reported by validation
+			}
+			if rf.autoSimple && !sch.includeAutoField(typ, rf.Name, rf.ManualGoType) {
+				continue
 			}
+			if _, defined := fields[rf.Name]; defined {
+				continue // later definition, reported by validation
+			}
+
+			funcType, _ := typ.resolvedFuncType(rf)
 
-			returnType := funcType.Out(0) // only use first return value, TODO: handle error
+			returnType := rf.returnGoType(funcType) // trailing error is handled in dynamicCallResolver
 			var fieldArgs graphql.FieldConfigArgument
 			var returnQLType graphql.Output
 			var qlTypeKind QLTypeKind = QLTypeKind_Simple
 
 			if rf.ManualType == nil {
-				returnQLType, qlTypeKind = getComplexQLType(returnType, rf.Name, qlTypes, qlConns)
+				returnQLType, qlTypeKind = sch.getComplexQLType(returnType, rf.Name, sch.nodeTypeName("", rf.ElemInterface), rf.IsConnection, qlTypes, qlConns)
 			} else {
 				// extension with manual return type, probably a embedded struct's field
 				returnQLType = rf.ManualType
@@ -71,49 +72,7 @@ func (sch *SchemaInfo) processObjectType(
 
 			resultIsConnection := qlTypeKind == QLTypeKind_Connection
 
-			funcArgs := make(graphql.FieldConfigArgument)
-
-			if rf.AutoArgs {
-				// use struct args
-				if funcType.NumIn() == 2 {
-					argStructType := funcType.In(1)
-					if argStructType.Kind() == reflect.Struct {
-						for i := 0; i < argStructType.NumField(); i++ {
-
-							argField := argStructType.Field(i)
-							argFieldName := lowerFirst(argField.Name)
-							argQLType := ToQLType(argField.Type)
-
-							var defaultValue interface{} = nil
-							if defTag := argField.Tag.Get(TAG_DefaultValue); defTag != "" {
-								defaultValue = ParseString(defTag, argField.Type)
-							}
-							if nonNullTag := argField.Tag.Get(TAG_NonNull); nonNullTag == "true" {
-								argQLType = graphql.NewNonNull(argQLType)
-							}
-							funcArgs[argFieldName] = &graphql.ArgumentConfig{
-								Type:         argQLType,
-								DefaultValue: defaultValue,
-							}
-						}
-					} else {
-						Warning("AutoArgs needs a struct value as argument", rf.MethodName)
-					}
-				}
-			} else {
-				// use manual argument info
-				for i := 1; i < funcType.NumIn(); i++ {
-					argQLType := ToQLType(funcType.In(i))
-					arg := rf.Args[i - 1]
-					if arg.NonNull {
-						argQLType = graphql.NewNonNull(argQLType)
-					}
-					funcArgs[arg.Name] = &graphql.ArgumentConfig{
-						Type:         argQLType,
-						DefaultValue: arg.DefaultValue,
-					}
-				}
-			}
+			funcArgs := sch.buildFieldArgs(funcType, rf.AutoArgs, rf.Args)
 
 			if qlTypeKind == QLTypeKind_Connection {
 				fieldArgs = relay.NewConnectionArgs(funcArgs)
@@ -140,37 +99,93 @@ func (sch *SchemaInfo) processObjectType(
 
 	qlTypeConf.Fields = fieldsGetter
 
-	if !typ.isRootType && !typ.isNonNode {
-		qlTypeConf.Interfaces = []*graphql.Interface{nodeDefinitions.NodeInterface}
-	}
+	qlTypeConf.Interfaces = graphql.InterfacesThunk(func() []*graphql.Interface {
+		var interfaces []*graphql.Interface
+		if typ.isNode() {
+			interfaces = append(interfaces, nodeDefinitions.NodeInterface)
+		}
+		for _, iface := range sch.interfaces {
+			if qlInterface, ok := sch.qlAbstractTypes[iface.Type].(*graphql.Interface); ok && typ.implements(iface.Type) {
+				interfaces = append(interfaces, qlInterface)
+			}
+		}
+		return interfaces
+	})
 	qlType := graphql.NewObject(qlTypeConf)
 	qlTypes[qlTypeConf.Name] = qlType
 
 	return qlType
 }
 
+// GraphQL arguments of a method or extension func, from its AutoArgs struct or manual argument info.
+func (sch *SchemaInfo) buildFieldArgs(funcType reflect.Type, autoArgs bool, args []ArgInfo) graphql.FieldConfigArgument {
+
+	funcArgs := make(graphql.FieldConfigArgument)
+
+	argIndex := firstArgIndex(funcType) // skip receiver/source and optional context
+
+	if autoArgs {
+		// use struct args
+		if numArgIn(funcType) == argIndex+1 {
+			argStructType := funcType.In(argIndex)
+			for i := 0; i < argStructType.NumField(); i++ {
+
+				argField := argStructType.Field(i)
+				argFieldName, ok := sch.fieldName(argField)
+				if !ok {
+					continue // unexported or excluded by its tag
+				}
+				argQLType, defaultValue := sch.inputFieldQLType(argField) // type, nonNull, gqlid and def tags
+				funcArgs[argFieldName] = &graphql.ArgumentConfig{
+					Type:         argQLType,
+					DefaultValue: defaultValue,
+				}
+			}
+		}
+	} else {
+		// use manual argument info
+		for i := argIndex; i < numArgIn(funcType); i++ {
+			argQLType := sch.toQLInputType(funcType.In(i))
+			arg := args[i-argIndex]
+			if arg.NonNull {
+				argQLType = graphql.NewNonNull(argQLType)
+			}
+			funcArgs[arg.Name] = &graphql.ArgumentConfig{
+				Type:         argQLType,
+				DefaultValue: arg.DefaultValue,
+			}
+		}
+	}
+	return funcArgs
+}
+
+// Whether a field added by SimpleFields is included, its type needs to resolve without registered object types
+// and no field with the same name is defined explicitly.
+func (sch *SchemaInfo) includeAutoField(typ *TypeInfo, name string, goType reflect.Type) bool {
+	return sch.toQLType(goType) != nil && !typ.hasExplicitField(name)
+}
+
 func (sch *SchemaInfo) dynamicCallResolver(
 	rf ResolvedFieldInfo,
 	funcType reflect.Type,
 	typ *TypeInfo,
 	fieldArgs graphql.FieldConfigArgument,
 	resultIsConnection bool,
-	p graphql.ResolveParams) (interface{}, error) {
+	p graphql.ResolveParams) (result interface{}, err error) {
 
 	defer func() {
 		if e := recover(); e != nil {
-			fmt.Printf("%s: %s", e, debug.Stack())
+			result, err = nil, sch.recoverPanic(p.Context, typ.Name, rf.Name, e)
 		}
 	}()
 
-	fmt.Println("resultIsConnection", resultIsConnection)
-	fmt.Println("[dynamicCallResolver]", "funcType=", funcType, "rf=", rf, "typ=", typ.Name)
+	sch.logger.Debug("Calling resolver", "type", typ.Name, "field", rf.Name, "funcType", funcType, "connection", resultIsConnection)
 
 	var objVal reflect.Value
 	if typ.isRootType {
 		objVal = reflect.ValueOf(sch.rootInstance)
 	} else {
-		objVal = reflect.ValueOf(p.Source) // p.Source would be a pointer to struct
+		objVal = typ.sourceValue(p.Source) // pointer to struct, also for values and structs embedding it
 	}
 	if !objVal.IsValid() {
 		return nil, errors.New("Cannot get source object when calling " + rf.MethodName)
@@ -192,27 +207,41 @@ func (sch *SchemaInfo) dynamicCallResolver(
 	var inValues []reflect.Value
 
 	if isExtensionCall {
+		if funcType.In(0) == typ.Type {
+			objVal = objVal.Elem() // extension func takes the struct value
+		}
 		inValues = append(inValues, objVal) // first argument needs to be the source object
 	}
 
+	argIndex := firstArgIndex(funcType)
+	if argIndex == 2 {
+		inValues = append(inValues, contextValue(p.Context)) // inject request context
+	}
+
+	given := givenArgs(p.Info, p.Context) // tells omitted arguments from explicit nulls
+
 	if rf.AutoArgs {
 		// use struct args
-		if funcType.NumIn() == 2 {
-			argStructType := funcType.In(1)
+		if numArgIn(funcType) == argIndex+1 {
+			argStructType := funcType.In(argIndex)
 			argStructVal := reflect.New(argStructType).Elem()
 
 			for i := 0; i < argStructVal.NumField(); i++ {
 				argStructField := argStructType.Field(i)
 				argStructFieldVal := argStructVal.Field(i)
-				lowerFirstFieldName := lowerFirst(argStructField.Name)
+				argFieldName, ok := sch.fieldName(argStructField)
+				if !ok {
+					continue
+				}
 
 				var argObj interface{} = nil
 				var hasInput bool
-				if argObj, hasInput = p.Args[lowerFirstFieldName]; !hasInput {
-					argObj = fieldArgs[lowerFirstFieldName].DefaultValue
+				if argObj, hasInput = p.Args[argFieldName]; !hasInput {
+					argObj = fieldArgs[argFieldName].DefaultValue
 				}
-				if argObj != nil {
-					argStructFieldVal.Set(reflect.ValueOf(argObj)) // bind field value
+				argGiven, isGiven := given[argFieldName]
+				if err := sch.assignInputField(argStructFieldVal, argStructField, argObj, argGiven, isGiven); err != nil { // bind field value
+					return nil, &FieldError{TypeName: typ.Name, FieldName: rf.Name, Err: fmt.Errorf("argument %s: %v", argFieldName, err)}
 				}
 			}
 			inValues = append(inValues, argStructVal)
@@ -220,27 +249,76 @@ func (sch *SchemaInfo) dynamicCallResolver(
 
 	} else {
 		// use plain args
-		for _, arg := range rf.Args {
+		for i, arg := range rf.Args {
 			var argObj interface{}
 			var hasInput bool
 			if argObj, hasInput = p.Args[arg.Name]; !hasInput {
 				argObj = arg.DefaultValue
 			}
-			inValues = append(inValues, reflect.ValueOf(argObj))
+			argVal := reflect.New(funcType.In(argIndex + i)).Elem()
+			argGiven, isGiven := given[arg.Name]
+			if err := sch.assignInput(argVal, argObj, argGiven, isGiven); err != nil {
+				return nil, &FieldError{TypeName: typ.Name, FieldName: rf.Name, Err: fmt.Errorf("argument %s: %v", arg.Name, err)}
+			}
+			inValues = append(inValues, argVal)
+		}
+	}
+
+	if hasPageArgs(funcType) {
+		pageArgs, err := newPageArgs(p.Args)
+		if err != nil {
+			return nil, &FieldError{TypeName: typ.Name, FieldName: rf.Name, Err: err}
 		}
+		inValues = append(inValues, reflect.ValueOf(pageArgs))
 	}
 
 	outValues := funcVal.Call(inValues)
 
+	if err := errorFromOut(funcType, outValues); err != nil {
+		return nil, sch.fieldError(p.Context, typ.Name, rf.Name, err)
+	}
+
 	out := outValues[0].Interface()
 
-	fmt.Println(funcType, rf.MethodName, "Out:", out)
+	sch.logger.Debug("Resolver returned", "type", typ.Name, "field", rf.Name, "out", out)
 
-	if resultIsConnection {
-		resultSlice := toEmptyInterfaceSlice(out)
-		// TODO: manage pagination
-		return relay.ConnectionFromArray(resultSlice, relay.NewConnectionArguments(p.Args)), nil
-	} else {
-		return out, nil
+	complete := func(out interface{}) (interface{}, error) {
+		if resultIsConnection {
+			conn, err := resolveConnection(p.Context, out, p.Args)
+			if err != nil {
+				return nil, &FieldError{TypeName: typ.Name, FieldName: rf.Name, Err: err}
+			}
+			return conn, nil
+		} else {
+			return out, nil
+		}
+	}
+
+	if deferredResultType(funcType.Out(0)) != nil {
+		// called by the executor after sibling fields are resolved, so loaders can batch their keys
+		thunk := deferredThunk(outValues[0], complete)
+		return extendedErrorThunk(p.Info, p.Info.Path, func() (result interface{}, err error) {
+			defer func() {
+				if e := recover(); e != nil {
+					result, err = nil, sch.recoverPanic(p.Context, typ.Name, rf.Name, e)
+				}
+			}()
+			if result, err = thunk(); err != nil {
+				err = sch.fieldError(p.Context, typ.Name, rf.Name, err)
+			}
+			return result, err
+		}), nil
+	}
+	return complete(out)
+}
+
+// Resolver of a simple field reading the struct field by its Go name, the GraphQL name may differ.
+// Sources without a value of the type, e.g. maps, are resolved with graphql.DefaultResolveFn.
+func (typ *TypeInfo) simpleFieldResolver(goName string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if source := typ.sourceValue(p.Source); source.IsValid() {
+			return source.Elem().FieldByName(goName).Interface(), nil
+		}
+		return graphql.DefaultResolveFn(p)
 	}
 }
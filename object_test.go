@@ -0,0 +1,280 @@
+package gographer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+type objectTestItem struct {
+	Name string `json:"name"`
+}
+
+func (item *objectTestItem) GetGood() (string, error) {
+	return "good", nil
+}
+
+func (item *objectTestItem) GetBad() (string, error) {
+	return "", errors.New("bad field")
+}
+
+type objectTestRoot struct{}
+
+func (r *objectTestRoot) GetItem() (*objectTestItem, error) {
+	return &objectTestItem{Name: "item"}, nil
+}
+
+func (r *objectTestRoot) GetMissing() (*objectTestItem, error) {
+	return nil, errors.New("no item")
+}
+
+type objectTestMutation struct{}
+
+type objectTestCreateInput struct {
+	Name string `json:"name"`
+}
+
+type objectTestCreatePayload struct {
+	Item *objectTestItem `json:"item"`
+}
+
+func (m *objectTestMutation) Create(in objectTestCreateInput) (*objectTestCreatePayload, error) {
+	if in.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	return &objectTestCreatePayload{Item: &objectTestItem{Name: in.Name}}, nil
+}
+
+func TestResolverErrors(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&objectTestItem{}).SetNonNode().SimpleFields().ResolvedFields().
+		ExtensionField("ext", func(item *objectTestItem) (string, error) { return "", errors.New("bad extension") }, nil)
+	sch.RegType(&objectTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&objectTestMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ item { name good } }`, `{"data":{"item":{"good":"good","name":"item"}}}`},
+		// errors have the field path, sibling fields are still resolved
+		{`{ item { name bad good } }`, `{"data":{"item":{"bad":null,"good":"good","name":"item"}},"errors":[{"message":"bad field","locations":[{"line":1,"column":15}],"path":["item","bad"]}]}`},
+		{`{ item { ext name } }`, `{"data":{"item":{"ext":null,"name":"item"}},"errors":[{"message":"bad extension","locations":[{"line":1,"column":10}],"path":["item","ext"]}]}`},
+		{`{ missing { name } item { name } }`, `{"data":{"item":{"name":"item"},"missing":null},"errors":[{"message":"no item","locations":[{"line":1,"column":3}],"path":["missing"]}]}`},
+		{`mutation { a: create(input: {name: ""}) { item { name } } b: create(input: {name: "b"}) { item { name } } }`,
+			`{"data":{"a":null,"b":{"item":{"name":"b"}}},"errors":[{"message":"name is required","locations":[{"line":1,"column":12}],"path":["a"]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, nil); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+}
+
+type objectTestUserKey struct{}
+
+func objectTestUser(ctx context.Context) string {
+	user, _ := ctx.Value(objectTestUserKey{}).(string)
+	return user
+}
+
+type objectTestContextRoot struct{}
+
+type objectTestGreetArgs struct {
+	Greeting string `json:"greeting"`
+}
+
+func (r *objectTestContextRoot) GetUser(ctx context.Context) string {
+	return objectTestUser(ctx)
+}
+
+func (r *objectTestContextRoot) GetGreet(ctx context.Context, args objectTestGreetArgs) string {
+	return args.Greeting + " " + objectTestUser(ctx)
+}
+
+func (r *objectTestContextRoot) Repeat(ctx context.Context, times int) string {
+	return fmt.Sprint(times, " ", objectTestUser(ctx))
+}
+
+type objectTestContextMutation struct{}
+
+type objectTestRenameInput struct {
+	Name string `json:"name"`
+}
+
+type objectTestRenamePayload struct {
+	RenamedBy string `json:"renamedBy"`
+}
+
+func (m *objectTestContextMutation) Rename(ctx context.Context, in objectTestRenameInput) (*objectTestRenamePayload, error) {
+	return &objectTestRenamePayload{RenamedBy: objectTestUser(ctx)}, nil
+}
+
+func TestContextArgs(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&objectTestContextRoot{}).SetRoot().ResolvedFields().
+		ResolvedField("repeat", "Repeat", []ArgInfo{{Name: "times", NonNull: true}}).
+		ExtensionField("ext", func(r *objectTestContextRoot, ctx context.Context, args objectTestGreetArgs) string {
+			return args.Greeting + " from " + objectTestUser(ctx)
+		}, AutoArgs)
+	sch.RegType(&objectTestContextMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ user greet(greeting: "hi") repeat(times: 2) ext(greeting: "hello") }`, `{"data":{"ext":"hello from ann","greet":"hi ann","repeat":"2 ann","user":"ann"}}`},
+		{`mutation { rename(input: {name: "x"}) { renamedBy } }`, `{"data":{"rename":{"renamedBy":"ann"}}}`},
+	}
+	ctx := context.WithValue(context.Background(), objectTestUserKey{}, "ann")
+	for _, test := range tests {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: test.query, Context: ctx})
+		if got, _ := json.Marshal(result); string(got) != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+	// context parameters are not GraphQL arguments
+	for _, field := range []string{"user", "greet", "repeat", "ext"} {
+		var args []string
+		for _, arg := range schema.QueryType().Fields()[field].Args {
+			args = append(args, arg.Name())
+		}
+		if len(args) > 1 {
+			t.Errorf("field %s: got arguments %v", field, args)
+		}
+	}
+}
+
+type objectTestTodo struct {
+	Title string `json:"title"`
+}
+
+func (todo *objectTestTodo) GetParent() *objectTestTodo {
+	if todo.Title == "child" {
+		return &objectTestTodo{Title: "parent"}
+	}
+	return nil
+}
+
+func (todo *objectTestTodo) GetOwner() *objectTestOwner {
+	return &objectTestOwner{Name: "ann"}
+}
+
+type objectTestOwner struct {
+	Name string `json:"name"`
+}
+
+func (user *objectTestOwner) GetTodos() []*objectTestTodo {
+	return []*objectTestTodo{{Title: "child"}}
+}
+
+type objectTestTodoRoot struct{}
+
+func (r *objectTestTodoRoot) GetTodo() *objectTestTodo {
+	return &objectTestTodo{Title: "child"}
+}
+
+func (r *objectTestTodoRoot) GetStranger() *objectTestUnregistered {
+	return nil
+}
+
+type objectTestUnregistered struct{}
+
+func TestTypeOrder(t *testing.T) {
+	// root first, types referencing each other and themselves later
+	sch := NewSchemaInfo()
+	sch.RegType(&objectTestTodoRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&objectTestOwner{}).SetNonNode().SimpleFields().ResolvedFields()
+	sch.RegType(&objectTestTodo{}).SetNonNode().SimpleFields().ResolvedFields()
+	schema, err := sch.GetSchema()
+	if !hasSchemaError(err, "objectTestTodoRoot", "return type *gographer.objectTestUnregistered: type objectTestUnregistered is not registered, use RegType") {
+		t.Errorf("unregistered type not reported: %v", err)
+	}
+	query := `{ todo { title parent { title parent { title } } owner { name todos { title owner { name } } } } }`
+	want := `{"data":{"todo":{"owner":{"name":"ann","todos":[{"owner":{"name":"ann"},"title":"child"}]},"parent":{"parent":null,"title":"parent"},"title":"child"}}}`
+	if got := resultJSON(t, schema, query, nil); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+type objectTestEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type objectTestEntryRoot struct{}
+
+func objectTestEntries() []*objectTestEntry {
+	return []*objectTestEntry{{"1", "a"}, {"2", "b"}, {"3", "c"}}
+}
+
+func (r *objectTestEntryRoot) GetEntriesConnection() []*objectTestEntry {
+	return objectTestEntries()
+}
+
+func (r *objectTestEntryRoot) Search(prefix string) []*objectTestEntry {
+	var found []*objectTestEntry
+	for _, entry := range objectTestEntries() {
+		if entry.Name >= prefix {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+func (r *objectTestEntryRoot) GetNamesConnection() []string {
+	return nil
+}
+
+type objectTestEntryMutation struct{}
+
+type objectTestResetInput struct{}
+
+type objectTestResetPayload struct {
+	Entries []*objectTestEntry `json:"entries" connection:"true"`
+}
+
+func (m *objectTestEntryMutation) Reset(in objectTestResetInput) (*objectTestResetPayload, error) {
+	return &objectTestResetPayload{Entries: objectTestEntries()}, nil
+}
+
+func TestConnectionFields(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&objectTestEntry{}).IDField("id", nil).SimpleFields()
+	sch.RegType(&objectTestEntryRoot{}).SetRoot().ResolvedFields().
+		ConnectionField("search", "Search", []ArgInfo{{Name: "prefix"}})
+	sch.RegType(&objectTestEntryMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if !hasSchemaError(err, "objectTestEntryRoot", "return type []string: connection node type string is not a registered object type") {
+		t.Errorf("connection of strings not reported: %v", err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{`{ entries(first: 2) { edges { cursor node { name } } pageInfo { hasNextPage hasPreviousPage } } }`,
+			`{"data":{"entries":{"edges":[{"cursor":"YXJyYXljb25uZWN0aW9uOjA=","node":{"name":"a"}},{"cursor":"YXJyYXljb25uZWN0aW9uOjE=","node":{"name":"b"}}],"pageInfo":{"hasNextPage":true,"hasPreviousPage":false}}}}`},
+		{`{ entries(after: "YXJyYXljb25uZWN0aW9uOjA=") { edges { node { name } } } }`,
+			`{"data":{"entries":{"edges":[{"node":{"name":"b"}},{"node":{"name":"c"}}]}}}`},
+		{`{ search(prefix: "b", last: 1) { edges { node { name } } pageInfo { hasPreviousPage } } }`,
+			`{"data":{"search":{"edges":[{"node":{"name":"c"}}],"pageInfo":{"hasPreviousPage":true}}}}`},
+		{`mutation { reset(input: {}) { entries(first: 1) { edges { node { name } } } } }`,
+			`{"data":{"reset":{"entries":{"edges":[{"node":{"name":"a"}}]}}}}`},
+		{`{ names }`,
+			`{"data":null,"errors":[{"message":"Cannot query field \"names\" on type \"objectTestEntryRoot\". Did you mean \"nodes\"?","locations":[{"line":1,"column":3}]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, nil); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+}
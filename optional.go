@@ -0,0 +1,131 @@
+package gographer
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"golang.org/x/net/context"
+	"reflect"
+)
+
+type OptionalState int
+
+const (
+	OptionalState_Omitted OptionalState = iota
+	OptionalState_Null
+	OptionalState_Set
+)
+
+// Optional tells whether an argument or input field was omitted, explicitly null or set.
+// Embed it in a struct with a Value field to wrap any input type, like OptionalString.
+// graphql-go drops null variables when coercing them, explicit nulls are only reported
+// for requests whose context carries the raw variables, see WithVariables.
+type Optional struct {
+	State OptionalState
+}
+
+func (opt Optional) IsOmitted() bool {
+	return opt.State == OptionalState_Omitted
+}
+
+func (opt Optional) IsNull() bool {
+	return opt.State == OptionalState_Null
+}
+
+func (opt Optional) IsSet() bool {
+	return opt.State == OptionalState_Set
+}
+
+type OptionalString struct {
+	Optional
+	Value string
+}
+
+type OptionalInt struct {
+	Optional
+	Value int
+}
+
+type OptionalFloat struct {
+	Optional
+	Value float64
+}
+
+type OptionalBool struct {
+	Optional
+	Value bool
+}
+
+var optionalType = reflect.TypeOf(Optional{})
+
+// The Value field of a struct embedding Optional as first field.
+func optionalValueField(typ reflect.Type) (reflect.StructField, bool) {
+	if typ.Kind() != reflect.Struct || typ.NumField() == 0 {
+		return reflect.StructField{}, false
+	}
+	if first := typ.Field(0); !first.Anonymous || first.Type != optionalType {
+		return reflect.StructField{}, false
+	}
+	return typ.FieldByName("Value")
+}
+
+type variablesKey struct{}
+
+// WithVariables returns a context carrying the raw request variables, pass it to graphql.Do
+// so variables set to null are reported as null instead of omitted.
+func WithVariables(ctx context.Context, variables map[string]interface{}) context.Context {
+	return context.WithValue(ctx, variablesKey{}, variables)
+}
+
+// Arguments of the field as given in the query, explicit nulls are kept as nil,
+// omitted arguments and input fields are left out. Values of literals are not converted,
+// p.Args still provides the values.
+func givenArgs(info graphql.ResolveInfo, ctx context.Context) map[string]interface{} {
+	given := make(map[string]interface{})
+	if len(info.FieldASTs) == 0 {
+		return given
+	}
+	var rawVariables map[string]interface{}
+	if ctx != nil {
+		rawVariables, _ = ctx.Value(variablesKey{}).(map[string]interface{})
+	}
+	for _, arg := range info.FieldASTs[0].Arguments {
+		if arg.Name == nil {
+			continue
+		}
+		if value, isGiven := givenValue(arg.Value, info.VariableValues, rawVariables); isGiven {
+			given[arg.Name.Value] = value
+		}
+	}
+	return given
+}
+
+func givenValue(value ast.Value, variables map[string]interface{}, rawVariables map[string]interface{}) (interface{}, bool) {
+	switch value := value.(type) {
+	case *ast.Variable:
+		name := value.Name.Value
+		if rawVariables != nil {
+			raw, isGiven := rawVariables[name]
+			return raw, isGiven
+		}
+		coerced := variables[name] // null and missing variables are both nil
+		return coerced, coerced != nil
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{})
+		for _, field := range value.Fields {
+			if fieldValue, isGiven := givenValue(field.Value, variables, rawVariables); isGiven {
+				obj[field.Name.Value] = fieldValue
+			}
+		}
+		return obj, true
+	case *ast.ListValue:
+		list := make([]interface{}, len(value.Values))
+		for i, item := range value.Values {
+			list[i], _ = givenValue(item, variables, rawVariables)
+		}
+		return list, true
+	case nil:
+		return nil, false
+	default:
+		return value.GetValue(), true
+	}
+}
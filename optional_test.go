@@ -0,0 +1,90 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+type optionalTestRoot struct{}
+
+type optionalTestInput struct {
+	Count OptionalInt `json:"count"`
+}
+
+type optionalTestArgs struct {
+	Name  OptionalString     `json:"name"`
+	Input *optionalTestInput `json:"input"`
+}
+
+// State and value of an optional, e.g. "set x".
+func describeOptional(opt Optional, value interface{}) string {
+	switch opt.State {
+	case OptionalState_Omitted:
+		return "omitted"
+	case OptionalState_Null:
+		return "null"
+	default:
+		return fmt.Sprint("set ", value)
+	}
+}
+
+func (r *optionalTestRoot) Echo(args optionalTestArgs) string {
+	out := "name " + describeOptional(args.Name.Optional, args.Name.Value)
+	if args.Input != nil {
+		out += ", count " + describeOptional(args.Input.Count.Optional, args.Input.Count.Value)
+	}
+	return out
+}
+
+func TestOptionalStates(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&optionalTestRoot{}).SetRoot().ResolvedField("echo", "Echo", AutoArgs)
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const withVariables = `query($n: String, $c: Int, $in: optionalTestInput) { a: echo(name: $n, input: {count: $c}) b: echo(input: $in) }`
+	tests := []struct {
+		name          string
+		query         string
+		variables     map[string]interface{}
+		withVariables bool // pass the raw variables in the context
+		want          map[string]interface{}
+	}{
+		{"omitted", `{ a: echo }`, nil, false,
+			map[string]interface{}{"a": "name omitted"}},
+		{"literals", `{ a: echo(name: "x", input: {count: 3}) }`, nil, false,
+			map[string]interface{}{"a": "name set x, count set 3"}},
+		{"empty input", `{ a: echo(input: {}) }`, nil, false,
+			map[string]interface{}{"a": "name omitted, count omitted"}},
+		{"omitted variables", withVariables, map[string]interface{}{}, true,
+			map[string]interface{}{"a": "name omitted, count omitted", "b": "name omitted"}},
+		{"null variables", withVariables, map[string]interface{}{"n": nil, "c": nil, "in": map[string]interface{}{"count": nil}}, true,
+			map[string]interface{}{"a": "name null, count null", "b": "name omitted, count null"}},
+		{"variables", withVariables, map[string]interface{}{"n": "x", "c": 3, "in": map[string]interface{}{"count": 4}}, true,
+			map[string]interface{}{"a": "name set x, count set 3", "b": "name omitted, count set 4"}},
+		{"null variables without raw variables", withVariables, map[string]interface{}{"n": nil, "c": nil}, false,
+			map[string]interface{}{"a": "name omitted, count omitted", "b": "name omitted"}},
+		{"variables without raw variables", withVariables, map[string]interface{}{"n": "x", "c": 3, "in": map[string]interface{}{"count": 4}}, false,
+			map[string]interface{}{"a": "name set x, count set 3", "b": "name omitted, count set 4"}},
+	}
+	for _, test := range tests {
+		ctx := context.Background()
+		if test.withVariables {
+			ctx = WithVariables(ctx, test.variables)
+		}
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: test.query, VariableValues: test.variables, Context: ctx})
+		if len(result.Errors) > 0 {
+			t.Errorf("%s: %v", test.name, result.Errors)
+			continue
+		}
+		data := result.Data.(map[string]interface{})
+		for alias, want := range test.want {
+			if data[alias] != want {
+				t.Errorf("%s: %s got %q, want %q", test.name, alias, data[alias], want)
+			}
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package gographer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"golang.org/x/net/context"
+	"reflect"
+	"strings"
+)
+
+// PageArgs are the connection arguments of a paged connection field.
+// A resolver receives them as last parameter, after its own arguments, e.g.
+// func (u *User) GetTodosConnection(in GetTodosInput, page PageArgs) *Page
+// registered with PagerField("todos", "GetTodosConnection", &Todo{}, AutoArgs).
+type PageArgs struct {
+	First  *int   // nil if not given
+	Last   *int   // nil if not given
+	After  string // cursor as given, see AfterOffset and AfterKey
+	Before string
+}
+
+// Page is one page of a connection, built by the resolver from a database query.
+// Cursors are taken from Cursors if given, otherwise from the KeyField of the nodes (keyset cursors),
+// otherwise from the node's offset (offset cursors, like relay.ConnectionFromArray).
+type Page struct {
+	Nodes           interface{} // slice of the node type
+	Cursors         []string    // cursor of each node
+	KeyField        string      // Go field name of the nodes' key for keyset cursors
+	Offset          int         // offset of the first node for offset cursors
+	TotalCount      int         // -1 if unknown
+	HasPreviousPage bool
+	HasNextPage     bool
+}
+
+// Pager loads a page of a connection, a resolver may return it instead of the page,
+// so the connection arguments don't have to be passed through.
+type Pager interface {
+	Page(ctx context.Context, args PageArgs) (*Page, error)
+}
+
+type PagerFunc func(ctx context.Context, args PageArgs) (*Page, error)
+
+func (f PagerFunc) Page(ctx context.Context, args PageArgs) (*Page, error) {
+	return f(ctx, args)
+}
+
+var (
+	pageArgsType = reflect.TypeOf(PageArgs{})
+	pagePtrType  = reflect.TypeOf(&Page{})
+	pagerType    = reflect.TypeOf((*Pager)(nil)).Elem()
+)
+
+const keysetCursorPrefix = "keyset:"
+
+// OffsetCursor is the cursor of the node at offset, compatible with relay.ConnectionFromArray.
+func OffsetCursor(offset int) string {
+	return string(relay.OffsetToCursor(offset))
+}
+
+// KeysetCursor is the cursor of a node with the key value, e.g. its ID or sort column.
+func KeysetCursor(key interface{}) string {
+	return base64.StdEncoding.EncodeToString([]byte(keysetCursorPrefix + fmt.Sprint(key)))
+}
+
+// Offset of an offset cursor.
+func CursorOffset(cursor string) (int, bool) {
+	if cursor == "" {
+		return 0, false
+	}
+	offset, err := relay.CursorToOffset(relay.ConnectionCursor(cursor))
+	return offset, err == nil
+}
+
+// Key value of a keyset cursor.
+func CursorKey(cursor string) (string, bool) {
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), keysetCursorPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(string(b), keysetCursorPrefix), true
+}
+
+func (args PageArgs) AfterOffset() (int, bool) {
+	return CursorOffset(args.After)
+}
+
+func (args PageArgs) BeforeOffset() (int, bool) {
+	return CursorOffset(args.Before)
+}
+
+func (args PageArgs) AfterKey() (string, bool) {
+	return CursorKey(args.After)
+}
+
+func (args PageArgs) BeforeKey() (string, bool) {
+	return CursorKey(args.Before)
+}
+
+// Offset and limit of the page for offset pagination of totalCount rows,
+// e.g. for SQL OFFSET and LIMIT, pass the result to OffsetPage.
+func (args PageArgs) OffsetLimit(totalCount int) (offset int, limit int) {
+	start, end := 0, totalCount
+	if after, ok := args.AfterOffset(); ok && after+1 > start {
+		start = after + 1
+	}
+	if before, ok := args.BeforeOffset(); ok && before < end {
+		end = before
+	}
+	if args.First != nil && start+*args.First < end {
+		end = start + *args.First
+	}
+	if args.Last != nil && end-*args.Last > start {
+		start = end - *args.Last
+	}
+	if end < start {
+		end = start
+	}
+	return start, end - start
+}
+
+// OffsetPage is the page of nodes loaded at offset of totalCount rows, with offset cursors.
+func OffsetPage(nodes interface{}, offset int, totalCount int) *Page {
+	return &Page{
+		Nodes:           nodes,
+		Offset:          offset,
+		TotalCount:      totalCount,
+		HasPreviousPage: offset > 0,
+		HasNextPage:     offset+reflect.ValueOf(nodes).Len() < totalCount,
+	}
+}
+
+// connection is the value of connection fields, relay.Connection with the total count.
+type connection struct {
+	Edges      []*relay.EdgeType `json:"edges"`
+	PageInfo   relay.PageInfo    `json:"pageInfo"`
+	TotalCount *int              `json:"totalCount"`
+}
+
+func connectionFields() graphql.Fields {
+	return graphql.Fields{
+		"totalCount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Number of items in the connection, null if unknown.",
+		},
+	}
+}
+
+// Connection of a whole slice, paged in memory.
+func arrayConnection(slice interface{}, args map[string]interface{}) *connection {
+	nodes := toEmptyInterfaceSlice(slice)
+	conn := relay.ConnectionFromArray(nodes, relay.NewConnectionArguments(args))
+	totalCount := len(nodes)
+	return &connection{Edges: conn.Edges, PageInfo: conn.PageInfo, TotalCount: &totalCount}
+}
+
+func pageConnection(page *Page) (*connection, error) {
+	conn := &connection{Edges: []*relay.EdgeType{}}
+	if page.Nodes != nil {
+		nodes := reflect.ValueOf(page.Nodes)
+		if nodes.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("page nodes need to be a slice, got %T", page.Nodes)
+		}
+		if page.Cursors != nil && len(page.Cursors) != nodes.Len() {
+			return nil, fmt.Errorf("page has %d nodes but %d cursors", nodes.Len(), len(page.Cursors))
+		}
+		for i := 0; i < nodes.Len(); i++ {
+			node := nodes.Index(i)
+			var cursor string
+			if page.Cursors != nil {
+				cursor = page.Cursors[i]
+			} else if page.KeyField != "" {
+				key := reflect.Indirect(node).FieldByName(page.KeyField)
+				if !key.IsValid() {
+					return nil, fmt.Errorf("page nodes have no key field %s", page.KeyField)
+				}
+				cursor = KeysetCursor(key.Interface())
+			} else {
+				cursor = OffsetCursor(page.Offset + i)
+			}
+			conn.Edges = append(conn.Edges, &relay.EdgeType{Node: node.Interface(), Cursor: relay.ConnectionCursor(cursor)})
+		}
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	conn.PageInfo.HasPreviousPage = page.HasPreviousPage
+	conn.PageInfo.HasNextPage = page.HasNextPage
+	if page.TotalCount >= 0 {
+		totalCount := page.TotalCount
+		conn.TotalCount = &totalCount
+	}
+	return conn, nil
+}
+
+func newPageArgs(args map[string]interface{}) (PageArgs, error) {
+	var pageArgs PageArgs
+	if first, ok := args["first"].(int); ok {
+		if first < 0 {
+			return pageArgs, errors.New("first must not be negative")
+		}
+		pageArgs.First = &first
+	}
+	if last, ok := args["last"].(int); ok {
+		if last < 0 {
+			return pageArgs, errors.New("last must not be negative")
+		}
+		pageArgs.Last = &last
+	}
+	pageArgs.After, _ = args["after"].(string)
+	pageArgs.Before, _ = args["before"].(string)
+	return pageArgs, nil
+}
+
+// Connection value of a resolver result, a Pager, a Page, a relay.Connection or a slice to page in memory.
+func resolveConnection(ctx context.Context, out interface{}, args map[string]interface{}) (interface{}, error) {
+	var page *Page
+	switch out := out.(type) {
+	case nil:
+		return nil, nil
+	case *relay.Connection:
+		if out == nil {
+			return nil, nil
+		}
+		return out, nil
+	case relay.Connection:
+		return &out, nil
+	case *Page:
+		page = out
+	case Pager:
+		pageArgs, err := newPageArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		if page, err = out.Page(ctx, pageArgs); err != nil {
+			return nil, err
+		}
+	default:
+		if reflect.ValueOf(out).Kind() != reflect.Slice {
+			return nil, fmt.Errorf("connection needs a slice, Page or Pager, got %T", out)
+		}
+		return arrayConnection(out, args), nil
+	}
+	if page == nil {
+		return nil, nil
+	}
+	return pageConnection(page)
+}
+
+// Whether the Go type is a Page or Pager, its node type is not known from the type.
+func isPageType(typ reflect.Type) bool {
+	return typ == pagePtrType || typ == pagerType
+}
+
+// Number of parameters of a method or extension func type, without a trailing PageArgs parameter.
+func numArgIn(funcType reflect.Type) int {
+	numIn := funcType.NumIn()
+	if numIn > firstArgIndex(funcType) && funcType.In(numIn-1) == pageArgsType {
+		return numIn - 1
+	}
+	return numIn
+}
+
+func hasPageArgs(funcType reflect.Type) bool {
+	return numArgIn(funcType) != funcType.NumIn()
+}
@@ -0,0 +1,145 @@
+package gographer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCursors(t *testing.T) {
+	tests := []struct {
+		cursor string
+		offset int // -1 if not an offset cursor
+		key    string
+		isKey  bool
+	}{
+		{OffsetCursor(0), 0, "", false},
+		{OffsetCursor(42), 42, "", false},
+		{KeysetCursor(42), -1, "42", true},
+		{KeysetCursor("a:b"), -1, "a:b", true},
+		{KeysetCursor(""), -1, "", true},
+		{"", -1, "", false},
+		{"not base64!", -1, "", false},
+		{"a2V5c2V0", -1, "", false}, // "keyset" without colon
+	}
+	for _, test := range tests {
+		offset, ok := CursorOffset(test.cursor)
+		if ok != (test.offset >= 0) || (ok && offset != test.offset) {
+			t.Errorf("CursorOffset(%q) = %d, %v, want %d", test.cursor, offset, ok, test.offset)
+		}
+		key, ok := CursorKey(test.cursor)
+		if key != test.key || ok != test.isKey {
+			t.Errorf("CursorKey(%q) = %q, %v, want %q, %v", test.cursor, key, ok, test.key, test.isKey)
+		}
+	}
+	if OffsetCursor(3) != "YXJyYXljb25uZWN0aW9uOjM=" {
+		t.Errorf("offset cursor %q isn't compatible with relay.ConnectionFromArray", OffsetCursor(3))
+	}
+}
+
+func TestOffsetLimit(t *testing.T) {
+	n := func(i int) *int { return &i }
+	tests := []struct {
+		name       string
+		args       PageArgs
+		totalCount int
+		offset     int
+		limit      int
+	}{
+		{"all", PageArgs{}, 10, 0, 10},
+		{"empty", PageArgs{First: n(5)}, 0, 0, 0},
+		{"first", PageArgs{First: n(3)}, 10, 0, 3},
+		{"first beyond end", PageArgs{First: n(20)}, 10, 0, 10},
+		{"first zero", PageArgs{First: n(0)}, 10, 0, 0},
+		{"after", PageArgs{After: OffsetCursor(2)}, 10, 3, 7},
+		{"after and first", PageArgs{After: OffsetCursor(2), First: n(3)}, 10, 3, 3},
+		{"after near end", PageArgs{After: OffsetCursor(8), First: n(5)}, 10, 9, 1},
+		{"after end", PageArgs{After: OffsetCursor(20)}, 10, 21, 0},
+		{"last", PageArgs{Last: n(3)}, 10, 7, 3},
+		{"last beyond start", PageArgs{Last: n(20)}, 10, 0, 10},
+		{"before", PageArgs{Before: OffsetCursor(5)}, 10, 0, 5},
+		{"before and last", PageArgs{Before: OffsetCursor(5), Last: n(2)}, 10, 3, 2},
+		{"before start", PageArgs{Before: OffsetCursor(0)}, 10, 0, 0},
+		{"before end", PageArgs{Before: OffsetCursor(20)}, 10, 0, 10},
+		{"after and before", PageArgs{After: OffsetCursor(2), Before: OffsetCursor(6)}, 10, 3, 3},
+		{"after past before", PageArgs{After: OffsetCursor(5), Before: OffsetCursor(3)}, 10, 6, 0},
+		{"first and last", PageArgs{First: n(6), Last: n(2)}, 10, 4, 2},
+		{"keyset cursor", PageArgs{After: KeysetCursor(2), First: n(3)}, 10, 0, 3},
+		{"invalid cursor", PageArgs{After: "invalid", Before: "invalid"}, 10, 0, 10},
+	}
+	for _, test := range tests {
+		offset, limit := test.args.OffsetLimit(test.totalCount)
+		if offset != test.offset || limit != test.limit {
+			t.Errorf("%s: got offset %d, limit %d, want %d, %d", test.name, offset, limit, test.offset, test.limit)
+		}
+	}
+}
+
+func TestPageConnection(t *testing.T) {
+	type node struct{ ID int }
+	nodes := []*node{{4}, {5}}
+	tests := []struct {
+		name    string
+		page    *Page
+		cursors []string
+		err     string
+	}{
+		{"offset", OffsetPage(nodes, 3, 10), []string{OffsetCursor(3), OffsetCursor(4)}, ""},
+		{"keyset", &Page{Nodes: nodes, KeyField: "ID"}, []string{KeysetCursor(4), KeysetCursor(5)}, ""},
+		{"given", &Page{Nodes: nodes, Cursors: []string{"a", "b"}}, []string{"a", "b"}, ""},
+		{"empty", &Page{}, nil, ""},
+		{"missing cursors", &Page{Nodes: nodes, Cursors: []string{"a"}}, nil, "page has 2 nodes but 1 cursors"},
+		{"missing key field", &Page{Nodes: nodes, KeyField: "Key"}, nil, "page nodes have no key field Key"},
+		{"not a slice", &Page{Nodes: nodes[0]}, nil, "page nodes need to be a slice, got *gographer.node"},
+	}
+	for _, test := range tests {
+		conn, err := pageConnection(test.page)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%s: got error %v, want %q", test.name, err, test.err)
+			}
+			continue
+		}
+		var cursors []string
+		for _, edge := range conn.Edges {
+			cursors = append(cursors, string(edge.Cursor))
+		}
+		if err != nil || !reflect.DeepEqual(cursors, test.cursors) {
+			t.Errorf("%s: got cursors %v, %v, want %v", test.name, cursors, err, test.cursors)
+		}
+	}
+	conn, _ := pageConnection(OffsetPage(nodes, 3, 10))
+	if !conn.PageInfo.HasPreviousPage || !conn.PageInfo.HasNextPage || *conn.TotalCount != 10 ||
+		string(conn.PageInfo.StartCursor) != OffsetCursor(3) || string(conn.PageInfo.EndCursor) != OffsetCursor(4) {
+		t.Errorf("got page info %+v, total count %d", conn.PageInfo, *conn.TotalCount)
+	}
+	if conn, _ := pageConnection(&Page{TotalCount: -1}); conn.TotalCount != nil {
+		t.Errorf("unknown total count is %d", *conn.TotalCount)
+	}
+}
+
+func TestNewPageArgs(t *testing.T) {
+	n := func(i int) *int { return &i }
+	tests := []struct {
+		args map[string]interface{}
+		want PageArgs
+		err  string
+	}{
+		{map[string]interface{}{}, PageArgs{}, ""},
+		{map[string]interface{}{"first": 2, "after": "a"}, PageArgs{First: n(2), After: "a"}, ""},
+		{map[string]interface{}{"last": 0, "before": "b"}, PageArgs{Last: n(0), Before: "b"}, ""},
+		{map[string]interface{}{"first": -1}, PageArgs{}, "first must not be negative"},
+		{map[string]interface{}{"last": -1}, PageArgs{}, "last must not be negative"},
+	}
+	for _, test := range tests {
+		pageArgs, err := newPageArgs(test.args)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%v: got error %v, want %q", test.args, err, test.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(pageArgs, test.want) {
+			t.Errorf("%v: got %+v, %v, want %+v", test.args, pageArgs, err, test.want)
+		}
+	}
+}
@@ -1,14 +1,29 @@
 package gographer
 
 import (
+	"fmt"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/relay"
 	"golang.org/x/net/context"
 	"reflect"
 )
 
+// GetSchema validates the registered types and builds the GraphQL schema.
+// Registration errors are returned together as SchemaErrors, in strict mode no schema is built,
+// otherwise the schema is built without the invalid fields, fields defined more than once keep their first definition.
 func (sch SchemaInfo) GetSchema() (graphql.Schema, error) {
 
+	problems := sch.validate()
+	if len(problems) > 0 {
+		if sch.strict {
+			return graphql.Schema{}, problems
+		}
+		for _, problem := range problems {
+			sch.logger.Warn("Schema registration error", "type", problem.TypeName, "field", problem.FieldName, "error", problem.Message)
+		}
+	}
+	sch.invalidFields = problems.fieldSet()
+
 	qlTypes := make(map[string]*graphql.Object)
 	qlConns := make(map[string]*relay.GraphQLConnectionDefinitions)
 	var rootType *graphql.Object
@@ -21,25 +36,30 @@ func (sch SchemaInfo) GetSchema() (graphql.Schema, error) {
 	nodeDefinitions = relay.NewNodeDefinitions(relay.NodeDefinitionsConfig{
 
 		IDFetcher: func(id string, info graphql.ResolveInfo, ctx context.Context) (interface{}, error) {
-			resolvedID := relay.FromGlobalID(id)
-			if typ, ok := sch.typesByName[resolvedID.Type]; ok {
-				return typ.idResolver(resolvedID.ID), nil
+			node, err := sch.fetchNode(id, info, ctx)
+			if thunk, ok := node.(func() (interface{}, error)); ok {
+				return extendedErrorThunk(info, info.Path, thunk), err // errors of batch ID resolvers keep their code
 			}
-			return nil, nil
+			return node, err
 		},
 
 		TypeResolve: func(value interface{}, info graphql.ResolveInfo) *graphql.Object {
-			type_ := reflect.ValueOf(value).Elem().Type()
-			if qlType, ok := qlTypes[type_.Name()]; ok {
-				return qlType
-			} else {
-				Warning("[GetSchema Error]", "cannot resolve type", value)
-				return nil
-			}
+			return sch.resolveObjectType(value, info, qlTypes)
 		},
 	})
+	sch.nodesField = sch.newNodesField(nodeDefinitions.NodeInterface)
 
-	// process all the object types, object types must be registered in order of dependency at the time
+	// interfaces and unions, object types are resolved when their fields and members are built
+	sch.qlAbstractTypes = make(map[reflect.Type]graphql.Output)
+	for _, iface := range sch.interfaces {
+		sch.qlAbstractTypes[iface.Type] = sch.processInterfaceType(iface, qlTypes, qlConns)
+	}
+	for _, union := range sch.unions {
+		sch.qlAbstractTypes[union.Type] = sch.processUnionType(union, qlTypes)
+	}
+
+	// process all the object types, fields are built lazily after every object type exists,
+	// so types can be registered in any order and may reference each other or themselves
 	for _, typ := range sch.types {
 		if !typ.isMutationType {
 
@@ -61,9 +81,108 @@ func (sch SchemaInfo) GetSchema() (graphql.Schema, error) {
 		}
 	}
 
+	// object types only reachable through interfaces
+	var extraTypes []graphql.Type
+	for _, typ := range sch.types {
+		if qlType, ok := qlTypes[typ.Name]; ok && !typ.isRootType {
+			extraTypes = append(extraTypes, qlType)
+		}
+	}
+
+	var directives []*graphql.Directive
+	if len(sch.directives) > 0 {
+		directives = append(append(directives, graphql.SpecifiedDirectives...), sch.directives...)
+	}
 	schema, err := graphql.NewSchema(graphql.SchemaConfig{
-		Query:    rootType,
-		Mutation: mutationType,
+		Query:      rootType,
+		Mutation:   mutationType,
+		Types:      extraTypes,
+		Directives: directives,
 	})
-	return schema, err
+	if err != nil {
+		if len(problems) > 0 {
+			return schema, append(problems, &SchemaError{Message: err.Error()})
+		}
+		return schema, err
+	}
+	sch.logger.Info("Schema built", "objectTypes", len(qlTypes), "connections", len(qlConns))
+	if len(problems) > 0 {
+		return schema, problems
+	}
+	return schema, nil
+}
+
+// Object of a global ID with the ID resolver of its type, nil if the resolver finds none.
+// Malformed IDs and IDs of unknown or non-node types are errors with ErrorCode_InvalidID.
+func (sch *SchemaInfo) fetchNode(id string, info graphql.ResolveInfo, ctx context.Context) (node interface{}, err error) {
+	var rootName string
+	if info.ParentType != nil {
+		rootName = info.ParentType.Name()
+	}
+	fail := func(code string, err error) error {
+		return &FieldError{TypeName: rootName, FieldName: info.FieldName, Err: err, Code: code}
+	}
+
+	resolvedID := relay.FromGlobalID(id)
+	if resolvedID == nil {
+		return nil, fail(ErrorCode_InvalidID, fmt.Errorf("invalid global ID %q", id))
+	}
+	typ, ok := sch.typesByName[resolvedID.Type]
+	if !ok {
+		return nil, fail(ErrorCode_InvalidID, fmt.Errorf("global ID %q has unknown type %s", id, resolvedID.Type))
+	}
+	if !typ.isNode() {
+		return nil, fail(ErrorCode_InvalidID, fmt.Errorf("global ID %q has type %s, which is not a node type", id, resolvedID.Type))
+	}
+
+	if typ.idLoader != nil {
+		load := typ.idLoader.Load(ctx, resolvedID.ID) // batched with the other IDs of the request
+		return func() (interface{}, error) {
+			node, err := load()
+			if err != nil {
+				return nil, sch.fieldError(ctx, rootName, info.FieldName, err)
+			}
+			return node, nil
+		}, nil
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			node, err = nil, sch.recoverPanic(ctx, rootName, info.FieldName, e)
+		}
+	}()
+	if node, err = typ.idResolver(ctx, resolvedID.ID); err != nil {
+		return nil, fail("", err)
+	}
+	return node, nil
+}
+
+// Root field nodes(ids:) fetching many objects like node, errors are reported for the IDs that cause them.
+func (sch *SchemaInfo) newNodesField(nodeInterface *graphql.Interface) *graphql.Field {
+	return &graphql.Field{
+		Name:        "nodes",
+		Description: "Fetches objects given their IDs",
+		Type:        graphql.NewNonNull(graphql.NewList(nodeInterface)),
+		Args: graphql.FieldConfigArgument{
+			"ids": &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID))),
+				Description: "The IDs of objects",
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			ids, _ := p.Args["ids"].([]interface{})
+			nodes := make([]interface{}, len(ids))
+			for i, id := range ids {
+				node, err := sch.fetchNode(fmt.Sprint(id), p.Info, p.Context)
+				if err != nil {
+					node = func() (interface{}, error) { return nil, err }
+				}
+				if thunk, ok := node.(func() (interface{}, error)); ok {
+					node = extendedErrorThunk(p.Info, p.Info.Path.WithKey(i), thunk) // errors are reported for the item, with their code
+				}
+				nodes[i] = node // thunks of batch ID resolvers are loaded together
+			}
+			return nodes, nil
+		},
+	}
 }
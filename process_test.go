@@ -0,0 +1,199 @@
+package gographer
+
+import (
+	"errors"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"golang.org/x/net/context"
+	"testing"
+)
+
+type nodeTestRoot struct{}
+
+type nodeTestItem struct {
+	ID string `json:"id"`
+}
+
+type nodeTestBatchItem struct {
+	ID string `json:"id"`
+}
+
+type nodeTestPlain struct {
+	ID string `json:"id"`
+}
+
+// Object of a test ID, "err" fails and "panic" panics.
+func nodeTestLookup(id string, newNode func(id string) interface{}) (interface{}, error) {
+	switch id {
+	case "err":
+		return nil, errors.New("lookup failed")
+	case "panic":
+		panic("secret lookup panic")
+	case "none":
+		return nil, nil
+	}
+	return newNode(id), nil
+}
+
+func newNodeTestSchema(t *testing.T, panics *int) graphql.Schema {
+	sch := NewSchemaInfo().SetStrict(true)
+	sch.SetPanicHandler(func(ctx context.Context, info *PanicInfo) error {
+		*panics++
+		return nil
+	})
+	sch.RegType(&nodeTestRoot{}).SetRoot()
+	sch.RegType(&nodeTestItem{}).IDField("id", nil).SetContextIDResolver(func(ctx context.Context, id string) (interface{}, error) {
+		return nodeTestLookup(id, func(id string) interface{} { return &nodeTestItem{id} })
+	})
+	sch.RegType(&nodeTestBatchItem{}).IDField("id", nil).SetBatchIDResolver(func(ctx context.Context, ids []string) ([]interface{}, error) {
+		nodes := make([]interface{}, len(ids))
+		for i, id := range ids {
+			node, err := nodeTestLookup(id, func(id string) interface{} { return &nodeTestBatchItem{id} })
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = node
+		}
+		return nodes, nil
+	})
+	sch.RegType(&nodeTestPlain{}).SimpleFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+func TestNodeErrors(t *testing.T) {
+	panics := 0
+	schema := newNodeTestSchema(t, &panics)
+	tests := []struct {
+		name    string
+		id      string
+		message string // of the error, empty if the ID resolves
+		code    string
+	}{
+		{"found", relay.ToGlobalID("nodeTestItem", "1"), "", ""},
+		{"found batched", relay.ToGlobalID("nodeTestBatchItem", "1"), "", ""},
+		{"not found", relay.ToGlobalID("nodeTestItem", "none"), "", ""},
+		{"not found batched", relay.ToGlobalID("nodeTestBatchItem", "none"), "", ""},
+		{"malformed", "garbage", `invalid global ID "garbage"`, ErrorCode_InvalidID},
+		{"unknown type", relay.ToGlobalID("Missing", "1"), `global ID "TWlzc2luZzox" has unknown type Missing`, ErrorCode_InvalidID},
+		{"not a node type", relay.ToGlobalID("nodeTestPlain", "1"), `global ID "bm9kZVRlc3RQbGFpbjox" has type nodeTestPlain, which is not a node type`, ErrorCode_InvalidID},
+		{"resolver error", relay.ToGlobalID("nodeTestItem", "err"), "lookup failed", ""},
+		{"resolver error batched", relay.ToGlobalID("nodeTestBatchItem", "err"), "lookup failed", ""},
+		{"resolver panic", relay.ToGlobalID("nodeTestItem", "panic"), DefaultPanicMessage, ErrorCode_Internal},
+		{"resolver panic batched", relay.ToGlobalID("nodeTestBatchItem", "panic"), DefaultPanicMessage, ErrorCode_Internal},
+	}
+	queries := map[string]string{
+		"node":  `query($id: ID!) { node(id: $id) { id } }`,
+		"nodes": `query($id: ID!) { nodes(ids: ["` + relay.ToGlobalID("nodeTestItem", "2") + `", $id]) { id } }`,
+	}
+	for field, query := range queries {
+		for _, test := range tests {
+			panics = 0
+			result := graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  query,
+				VariableValues: map[string]interface{}{"id": test.id},
+				Context:        WithLoaders(context.Background()),
+			})
+			if test.message == "" {
+				if len(result.Errors) > 0 {
+					t.Errorf("%s %s: unexpected errors %v", field, test.name, result.Errors)
+				}
+				continue
+			}
+			if len(result.Errors) != 1 {
+				t.Errorf("%s %s: got errors %v", field, test.name, result.Errors)
+				continue
+			}
+			resultErr := result.Errors[0]
+			var code interface{}
+			if resultErr.Extensions != nil {
+				code = resultErr.Extensions["code"]
+			}
+			if resultErr.Message != test.message || (test.code != "" && code != test.code) || (test.code == "" && code != nil) {
+				t.Errorf("%s %s: got %q with code %v, want %q with code %q", field, test.name, resultErr.Message, code, test.message, test.code)
+			}
+			wantPath := []interface{}{field}
+			if field == "nodes" {
+				wantPath = append(wantPath, 1)
+			}
+			if len(resultErr.Path) != len(wantPath) || resultErr.Path[len(resultErr.Path)-1] != wantPath[len(wantPath)-1] {
+				t.Errorf("%s %s: got path %v, want %v", field, test.name, resultErr.Path, wantPath)
+			}
+			if wantPanics := map[bool]int{true: 1}[test.code == ErrorCode_Internal]; panics != wantPanics {
+				t.Errorf("%s %s: panic handler called %d times, want %d", field, test.name, panics, wantPanics)
+			}
+		}
+	}
+}
+
+func TestNodesField(t *testing.T) {
+	schema := newNodeTestSchema(t, new(int))
+	ids := []interface{}{
+		relay.ToGlobalID("nodeTestItem", "1"),
+		relay.ToGlobalID("nodeTestBatchItem", "2"),
+		relay.ToGlobalID("nodeTestItem", "none"),
+		"garbage",
+		relay.ToGlobalID("nodeTestBatchItem", "3"),
+		relay.ToGlobalID("nodeTestItem", "1"),
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `query($ids: [ID!]!) { nodes(ids: $ids) { id __typename } }`,
+		VariableValues: map[string]interface{}{"ids": ids},
+		Context:        WithLoaders(context.Background()),
+	})
+	nodes, _ := result.Data.(map[string]interface{})["nodes"].([]interface{})
+	wantTypes := []interface{}{"nodeTestItem", "nodeTestBatchItem", nil, nil, "nodeTestBatchItem", "nodeTestItem"}
+	if len(nodes) != len(wantTypes) {
+		t.Fatalf("got nodes %v", result.Data)
+	}
+	for i, node := range nodes {
+		if wantTypes[i] == nil {
+			if node != nil {
+				t.Errorf("node %d: got %v, want null", i, node)
+			}
+			continue
+		}
+		obj, _ := node.(map[string]interface{})
+		if obj["__typename"] != wantTypes[i] || obj["id"] != ids[i] {
+			t.Errorf("node %d: got %v, want %s %s", i, node, wantTypes[i], ids[i])
+		}
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != ErrorCode_InvalidID ||
+		len(result.Errors[0].Path) != 2 || result.Errors[0].Path[1] != 3 {
+		t.Errorf("got errors %v, want INVALID_ID for nodes.3", result.Errors)
+	}
+}
+
+type nodeTestNoID struct {
+	ID string `json:"id"`
+}
+
+func TestNodeWithoutIDField(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegType(&nodeTestRoot{}).SetRoot()
+	sch.RegType(&nodeTestNoID{}).SimpleFields().SetIDResolver(func(id string) interface{} {
+		return &nodeTestNoID{id}
+	})
+	schema, err := sch.GetSchema()
+	if !hasSchemaError(err, "nodeTestNoID", "ID resolver needs an id field of type ID!") {
+		t.Errorf("missing IDField not reported: %v", err)
+	}
+	if schema.QueryType() == nil {
+		t.Fatal("lenient schema not built")
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ node(id: "` + relay.ToGlobalID("nodeTestNoID", "1") + `") { id } }`,
+	})
+	if len(result.Errors) != 1 || result.Errors[0].Extensions["code"] != ErrorCode_InvalidID {
+		t.Errorf("got errors %v, want INVALID_ID", result.Errors)
+	}
+	if _, err := sch.SetStrict(true).GetSchema(); !hasSchemaError(err, "nodeTestNoID", "ID resolver needs") {
+		t.Errorf("strict schema built without IDField: %v", err)
+	}
+}
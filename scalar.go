@@ -0,0 +1,176 @@
+package gographer
+
+import (
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"reflect"
+	"time"
+)
+
+// ScalarSerializeFn turns a Go value into a JSON serializable value, the error is reported as a field error.
+type ScalarSerializeFn func(value interface{}) (interface{}, error)
+
+// ScalarInfo maps a Go type to a custom GraphQL scalar, it takes precedence over the kind based mapping of ToQLType.
+type ScalarInfo struct {
+	Name         string
+	Type         reflect.Type
+	Description  string
+	serialize    ScalarSerializeFn
+	parseValue   graphql.ParseValueFn
+	parseLiteral graphql.ParseLiteralFn
+	qlScalar     *graphql.Scalar // built lazily
+}
+
+// Register a custom scalar for a Go type, replacing any scalar registered for the same type.
+// parseValue and parseLiteral return nil for invalid input, they may be nil for output only scalars.
+func (sch *SchemaInfo) RegScalar(
+	instance interface{},
+	serialize ScalarSerializeFn,
+	parseValue graphql.ParseValueFn,
+	parseLiteral graphql.ParseLiteralFn) *ScalarInfo {
+
+	type_ := reflect.TypeOf(instance)
+	scalar := &ScalarInfo{
+		Name:         sch.typeName(type_, TypeKind_Scalar),
+		Type:         type_,
+		serialize:    serialize,
+		parseValue:   parseValue,
+		parseLiteral: parseLiteral,
+	}
+	if _, exists := sch.scalarsByType[type_]; !exists {
+		sch.scalarTypes = append(sch.scalarTypes, type_)
+	}
+	sch.scalarsByType[type_] = scalar
+	return scalar
+}
+
+// Register a string scalar for a type implementing encoding.TextMarshaler,
+// input is supported if its pointer type implements encoding.TextUnmarshaler.
+func (sch *SchemaInfo) RegTextScalar(instance interface{}) *ScalarInfo {
+	type_ := reflect.TypeOf(instance)
+	parseValue := func(value interface{}) interface{} {
+		str, ok := value.(string)
+		if !ok {
+			return nil
+		}
+		ptrVal := reflect.New(type_)
+		unmarshaler, ok := ptrVal.Interface().(encoding.TextUnmarshaler)
+		if !ok {
+			return nil
+		}
+		if err := unmarshaler.UnmarshalText([]byte(str)); err != nil {
+			return nil
+		}
+		return ptrVal.Elem().Interface()
+	}
+	return sch.RegScalar(instance,
+		func(value interface{}) (interface{}, error) {
+			marshaler, ok := value.(encoding.TextMarshaler)
+			if !ok {
+				return nil, fmt.Errorf("%T is not an encoding.TextMarshaler", value)
+			}
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return nil, err
+			}
+			return string(text), nil
+		},
+		parseValue,
+		stringLiteralParser(parseValue))
+}
+
+func (scalar *ScalarInfo) SetName(name string) *ScalarInfo {
+	scalar.Name = name
+	scalar.qlScalar = nil
+	return scalar
+}
+
+func (scalar *ScalarInfo) SetDescription(description string) *ScalarInfo {
+	scalar.Description = description
+	scalar.qlScalar = nil
+	return scalar
+}
+
+func (scalar *ScalarInfo) qlType() *graphql.Scalar {
+	if scalar.qlScalar == nil {
+		scalar.qlScalar = graphql.NewScalar(graphql.ScalarConfig{
+			Name:         scalar.Name,
+			Description:  scalar.Description,
+			Serialize:    scalar.serializeValue,
+			ParseValue:   scalar.parseValue,
+			ParseLiteral: scalar.parseLiteral,
+		})
+	}
+	return scalar.qlScalar
+}
+
+func (scalar *ScalarInfo) serializeValue(value interface{}) interface{} {
+	val := reflect.ValueOf(value)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		value = val.Elem().Interface()
+	}
+	out, err := scalar.serialize(value)
+	if err != nil {
+		// the executor recovers it and reports a field error with path
+		panic(fmt.Errorf("cannot serialize %s: %v", scalar.Name, err))
+	}
+	return out
+}
+
+// Parse string literals with a value parser.
+func stringLiteralParser(parseValue graphql.ParseValueFn) graphql.ParseLiteralFn {
+	return func(valueAST ast.Value) interface{} {
+		if str, ok := valueAST.(*ast.StringValue); ok {
+			return parseValue(str.Value)
+		}
+		return nil
+	}
+}
+
+// Built-in scalars, registered by NewSchemaInfo.
+func (sch *SchemaInfo) regBuiltinScalars() {
+
+	sch.RegTextScalar(time.Time{}).
+		SetName("DateTime").
+		SetDescription("Date and time in RFC 3339 format")
+
+	parseDuration := func(value interface{}) interface{} {
+		if str, ok := value.(string); ok {
+			if d, err := time.ParseDuration(str); err == nil {
+				return d
+			}
+		}
+		return nil
+	}
+	sch.RegScalar(time.Duration(0),
+		func(value interface{}) (interface{}, error) {
+			return value.(time.Duration).String(), nil
+		},
+		parseDuration,
+		stringLiteralParser(parseDuration)).
+		SetName("Duration").
+		SetDescription("Duration in Go's time.ParseDuration format, e.g. 1h30m")
+
+	parseBase64 := func(value interface{}) interface{} {
+		if str, ok := value.(string); ok {
+			if b, err := base64.StdEncoding.DecodeString(str); err == nil {
+				return b
+			}
+		}
+		return nil
+	}
+	sch.RegScalar([]byte{},
+		func(value interface{}) (interface{}, error) {
+			return base64.StdEncoding.EncodeToString(value.([]byte)), nil
+		},
+		parseBase64,
+		stringLiteralParser(parseBase64)).
+		SetName("Base64").
+		SetDescription("Binary data encoded with standard base64")
+}
@@ -0,0 +1,108 @@
+package gographer
+
+import (
+	"errors"
+	"fmt"
+	"github.com/graphql-go/graphql/language/ast"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scalarTestColor struct {
+	R, G, B uint8
+}
+
+func scalarTestSerializeColor(value interface{}) (interface{}, error) {
+	color := value.(scalarTestColor)
+	if color.R == 1 {
+		return nil, errors.New("color cannot be serialized")
+	}
+	return fmt.Sprintf("#%02x%02x%02x", color.R, color.G, color.B), nil
+}
+
+func scalarTestParseColor(value interface{}) interface{} {
+	var color scalarTestColor
+	if str, ok := value.(string); ok {
+		if _, err := fmt.Sscanf(strings.ToLower(str), "#%02x%02x%02x", &color.R, &color.G, &color.B); err == nil {
+			return color
+		}
+	}
+	return nil
+}
+
+type scalarTestEvent struct {
+	At      time.Time       `json:"at"`
+	Takes   time.Duration   `json:"takes"`
+	Data    []byte          `json:"data"`
+	Color   scalarTestColor `json:"color"`
+	Invalid scalarTestColor `json:"invalid"`
+}
+
+type scalarTestRoot struct{}
+
+type scalarTestEventArgs struct {
+	At    time.Time       `json:"at"`
+	Takes time.Duration   `json:"takes"`
+	Data  []byte          `json:"data"`
+	Color scalarTestColor `json:"color"`
+}
+
+func (r *scalarTestRoot) GetEvent(args scalarTestEventArgs) *scalarTestEvent {
+	return &scalarTestEvent{At: args.At.Add(args.Takes), Takes: 2 * args.Takes, Data: append(args.Data, '!'), Color: args.Color, Invalid: scalarTestColor{R: 1}}
+}
+
+type scalarTestMutation struct{}
+
+type scalarTestDelayInput struct {
+	At    time.Time     `json:"at"`
+	Delay time.Duration `json:"delay"`
+}
+
+type scalarTestDelayPayload struct {
+	At time.Time `json:"at"`
+}
+
+func (m *scalarTestMutation) Delay(in scalarTestDelayInput) (*scalarTestDelayPayload, error) {
+	return &scalarTestDelayPayload{At: in.At.Add(in.Delay)}, nil
+}
+
+func TestScalars(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegScalar(scalarTestColor{}, scalarTestSerializeColor, scalarTestParseColor, func(valueAST ast.Value) interface{} {
+		if str, ok := valueAST.(*ast.StringValue); ok {
+			return scalarTestParseColor(str.Value)
+		}
+		return nil
+	}).SetName("Color")
+	sch.RegType(&scalarTestEvent{}).SetNonNode().SimpleFields()
+	sch.RegType(&scalarTestRoot{}).SetRoot().ResolvedFields()
+	sch.RegType(&scalarTestMutation{}).SetMutation().MutationFields()
+	schema, err := sch.GetSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{`{ event(at: "2020-01-02T03:04:05Z", takes: "1h30m", data: "aGk=", color: "#FF8000") { at takes data color } }`, nil,
+			`{"data":{"event":{"at":"2020-01-02T04:34:05Z","color":"#ff8000","data":"aGkh","takes":"3h0m0s"}}}`},
+		{`query($at: DateTime, $takes: Duration, $data: Base64, $color: Color) { event(at: $at, takes: $takes, data: $data, color: $color) { at takes data color } }`,
+			map[string]interface{}{"at": "2020-01-02T03:04:05+01:00", "takes": "10s", "data": "", "color": "#000000"},
+			`{"data":{"event":{"at":"2020-01-02T03:04:15+01:00","color":"#000000","data":"IQ==","takes":"20s"}}}`},
+		{`mutation { delay(input: {at: "2020-01-02T03:04:05Z", delay: "1m"}) { at } }`, nil,
+			`{"data":{"delay":{"at":"2020-01-02T03:05:05Z"}}}`},
+		// serialize errors are field errors
+		{`{ event { color invalid } }`, nil,
+			`{"data":{"event":{"color":"#000000","invalid":null}},"errors":[{"message":"cannot serialize Color: color cannot be serialized","locations":[{"line":1,"column":17}],"path":["event","invalid"]}]}`},
+		{`{ event(takes: "soon") { takes } }`, nil,
+			`{"data":null,"errors":[{"message":"Argument \"takes\" has invalid value \"soon\".\nExpected type \"Duration\", found \"soon\".","locations":[{"line":1,"column":16}]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, test.variables); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package gographer
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GetSDL builds the schema like GetSchema and prints it in GraphQL schema definition language,
+// registration errors are returned the same way.
+func (sch SchemaInfo) GetSDL() (string, error) {
+	schema, err := sch.GetSchema()
+	if schema.QueryType() == nil {
+		return "", err
+	}
+	return PrintSchema(schema), err
+}
+
+// PrintSchema prints the schema in GraphQL schema definition language.
+// Types, fields, arguments and enum values are sorted by name so the output is stable for diffs,
+// built-in scalars, introspection types and the standard directives are left out.
+func PrintSchema(schema graphql.Schema) string {
+	var blocks []string
+
+	if block := printSchemaDefinition(schema); block != "" {
+		blocks = append(blocks, block)
+	}
+
+	var directives []*graphql.Directive
+	for _, directive := range schema.Directives() {
+		if !isSpecifiedDirective(directive) {
+			directives = append(directives, directive)
+		}
+	}
+	sort.Slice(directives, func(i, j int) bool { return directives[i].Name < directives[j].Name })
+	for _, directive := range directives {
+		blocks = append(blocks, printDirective(directive))
+	}
+
+	typeMap := schema.TypeMap()
+	var typeNames []string
+	for name := range typeMap {
+		if !strings.HasPrefix(name, "__") && !isBuiltinScalarName(name) {
+			typeNames = append(typeNames, name)
+		}
+	}
+	sort.Strings(typeNames)
+	for _, name := range typeNames {
+		if block := printType(typeMap[name]); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// Schema definition, only needed if the root types are not named Query, Mutation and Subscription.
+func printSchemaDefinition(schema graphql.Schema) string {
+	query, mutation, subscription := schema.QueryType(), schema.MutationType(), schema.SubscriptionType()
+	if (query == nil || query.Name() == "Query") && (mutation == nil || mutation.Name() == "Mutation") &&
+		(subscription == nil || subscription.Name() == "Subscription") {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString("schema {\n")
+	if query != nil {
+		fmt.Fprintf(&buf, "  query: %s\n", query.Name())
+	}
+	if mutation != nil {
+		fmt.Fprintf(&buf, "  mutation: %s\n", mutation.Name())
+	}
+	if subscription != nil {
+		fmt.Fprintf(&buf, "  subscription: %s\n", subscription.Name())
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+func isSpecifiedDirective(directive *graphql.Directive) bool {
+	for _, specified := range graphql.SpecifiedDirectives {
+		if directive.Name == specified.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func isBuiltinScalarName(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+func printDirective(directive *graphql.Directive) string {
+	return printDescription(directive.Description, "") +
+		"directive @" + directive.Name + printArgs(directive.Args, "") +
+		" on " + strings.Join(directive.Locations, " | ")
+}
+
+func printType(ttype graphql.Type) string {
+	switch ttype := ttype.(type) {
+	case *graphql.Scalar:
+		return printDescription(ttype.Description(), "") + "scalar " + ttype.Name()
+	case *graphql.Object:
+		var interfaceNames []string
+		for _, iface := range ttype.Interfaces() {
+			interfaceNames = append(interfaceNames, iface.Name())
+		}
+		sort.Strings(interfaceNames)
+		implements := ""
+		if len(interfaceNames) > 0 {
+			implements = " implements " + strings.Join(interfaceNames, " & ")
+		}
+		return printDescription(ttype.Description(), "") + "type " + ttype.Name() + implements + printFields(ttype.Fields())
+	case *graphql.Interface:
+		return printDescription(ttype.Description(), "") + "interface " + ttype.Name() + printFields(ttype.Fields())
+	case *graphql.Union:
+		var memberNames []string
+		for _, member := range ttype.Types() {
+			memberNames = append(memberNames, member.Name())
+		}
+		sort.Strings(memberNames)
+		return printDescription(ttype.Description(), "") + "union " + ttype.Name() + " = " + strings.Join(memberNames, " | ")
+	case *graphql.Enum:
+		values := append([]*graphql.EnumValueDefinition(nil), ttype.Values()...)
+		sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+		var buf bytes.Buffer
+		buf.WriteString(printDescription(ttype.Description(), "") + "enum " + ttype.Name() + " {\n")
+		for _, value := range values {
+			buf.WriteString(printDescription(value.Description, "  ") + "  " + value.Name + printDeprecated(value.DeprecationReason) + "\n")
+		}
+		buf.WriteString("}")
+		return buf.String()
+	case *graphql.InputObject:
+		fields := ttype.Fields()
+		var fieldNames []string
+		for name := range fields {
+			fieldNames = append(fieldNames, name)
+		}
+		sort.Strings(fieldNames)
+		var buf bytes.Buffer
+		buf.WriteString(printDescription(ttype.Description(), "") + "input " + ttype.Name() + " {\n")
+		for _, name := range fieldNames {
+			field := fields[name]
+			buf.WriteString(printDescription(field.Description(), "  ") + "  " + name + ": " + field.Type.String() +
+				printDefaultValue(field.DefaultValue, field.Type) + "\n")
+		}
+		buf.WriteString("}")
+		return buf.String()
+	}
+	return ""
+}
+
+func printFields(fields graphql.FieldDefinitionMap) string {
+	var fieldNames []string
+	for name := range fields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+	var buf bytes.Buffer
+	buf.WriteString(" {\n")
+	for _, name := range fieldNames {
+		field := fields[name]
+		buf.WriteString(printDescription(field.Description, "  ") + "  " + name + printArgs(field.Args, "  ") +
+			": " + field.Type.String() + printDeprecated(field.DeprecationReason) + "\n")
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// Arguments in one line, or one per line if any has a description.
+func printArgs(args []*graphql.Argument, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	args = append([]*graphql.Argument(nil), args...)
+	sort.Slice(args, func(i, j int) bool { return args[i].Name() < args[j].Name() })
+	multiline := false
+	for _, arg := range args {
+		multiline = multiline || arg.Description() != ""
+	}
+	var printed []string
+	for _, arg := range args {
+		str := arg.Name() + ": " + arg.Type.String() + printDefaultValue(arg.DefaultValue, arg.Type)
+		if multiline {
+			str = printDescription(arg.Description(), indent+"  ") + indent + "  " + str
+		}
+		printed = append(printed, str)
+	}
+	if multiline {
+		return "(\n" + strings.Join(printed, "\n") + "\n" + indent + ")"
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == graphql.DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + printString(reason) + ")"
+}
+
+// GraphQL string literal, control characters are escaped as \n or \uXXXX, other characters are kept.
+func printString(str string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for _, r := range str {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+				fmt.Fprintf(&buf, `\u%04X`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// Description as block string on the lines before the definition.
+func printDescription(description string, indent string) string {
+	if description == "" {
+		return ""
+	}
+	description = strings.Replace(description, `"""`, `\"""`, -1)
+	if !strings.Contains(description, "\n") {
+		return indent + `"""` + description + `"""` + "\n"
+	}
+	lines := strings.Split(description, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + line
+		}
+	}
+	return indent + `"""` + "\n" + strings.Join(lines, "\n") + "\n" + indent + `"""` + "\n"
+}
+
+func printDefaultValue(value interface{}, ttype graphql.Input) string {
+	if value == nil {
+		return ""
+	}
+	return " = " + printValue(value, ttype)
+}
+
+// GraphQL literal of an input value of the type.
+func printValue(value interface{}, ttype graphql.Input) string {
+	if value == nil {
+		return "null"
+	}
+	switch ttype := ttype.(type) {
+	case *graphql.NonNull:
+		return printValue(value, ttype.OfType)
+	case *graphql.List:
+		val := reflect.ValueOf(value)
+		if val.Kind() != reflect.Slice {
+			return printValue(value, ttype.OfType) // single value coerces to a list
+		}
+		var items []string
+		for i := 0; i < val.Len(); i++ {
+			items = append(items, printValue(val.Index(i).Interface(), ttype.OfType))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *graphql.InputObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			break
+		}
+		fields := ttype.Fields()
+		var names []string
+		for name := range obj {
+			if _, ok := fields[name]; ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		var items []string
+		for _, name := range names {
+			items = append(items, name+": "+printValue(obj[name], fields[name].Type))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case *graphql.Enum:
+		for _, enumValue := range ttype.Values() {
+			if reflect.DeepEqual(enumValue.Value, value) {
+				return enumValue.Name
+			}
+		}
+		if name, ok := value.(string); ok {
+			return name
+		}
+	case *graphql.Scalar:
+		value = ttype.Serialize(value)
+	}
+	switch value := value.(type) {
+	case string:
+		return printString(value)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,163 @@
+package gographer
+
+import (
+	"github.com/graphql-go/graphql"
+	"testing"
+)
+
+type sdlTestStatus string
+
+type sdlTestNamed interface {
+	Title() string
+}
+
+type sdlTestResult interface {
+	isResult()
+}
+
+type sdlTestPost struct {
+	Body   string        `json:"body"`
+	Status sdlTestStatus `json:"status"`
+}
+
+func (p *sdlTestPost) Title() string {
+	return "post"
+}
+
+func (p *sdlTestPost) isResult() {}
+
+type sdlTestPerson struct {
+	Name string `json:"name"`
+}
+
+func (p *sdlTestPerson) Title() string {
+	return p.Name
+}
+
+func (p *sdlTestPerson) isResult() {}
+
+type sdlTestFilter struct {
+	Statuses []sdlTestStatus `json:"statuses"`
+	Text     string          `json:"text" def:"say \"hi\"\tthen\x01é"`
+}
+
+type sdlTestArgs struct {
+	Count  int            `json:"count" def:"3"`
+	Status sdlTestStatus  `json:"status" def:"ACTIVE"`
+	Filter *sdlTestFilter `json:"filter"`
+}
+
+type sdlTestRoot struct{}
+
+func (r *sdlTestRoot) GetSearch(args sdlTestArgs) []sdlTestNamed {
+	return nil
+}
+
+func (r *sdlTestRoot) GetResults() []sdlTestResult {
+	return nil
+}
+
+const sdlTestGolden = `schema {
+  query: sdlTestRoot
+}
+
+"""An object with an ID"""
+interface Node {
+  """The id of the object"""
+  id: ID!
+}
+
+input sdlTestFilterInput {
+  statuses: [sdlTestStatus]
+  text: String = "say \"hi\"\tthen\u0001é"
+}
+
+"""Something with a title"""
+interface sdlTestNamed {
+  title: String
+}
+
+type sdlTestPerson implements sdlTestNamed {
+  name: String
+  title: String
+}
+
+type sdlTestPost implements sdlTestNamed {
+  body: String
+  status: sdlTestStatus
+  title: String
+}
+
+union sdlTestResult = sdlTestPerson | sdlTestPost
+
+type sdlTestRoot {
+  """Fetches an object given its ID"""
+  node(
+    """The ID of an object"""
+    id: ID!
+  ): Node
+  """Fetches objects given their IDs"""
+  nodes(
+    """The IDs of objects"""
+    ids: [ID!]!
+  ): [Node]!
+  """
+  Old field
+  see search
+  """
+  old: String @deprecated(reason: "use search")
+  older: String @deprecated
+  results: [sdlTestResult]
+  search(count: Int = 3, filter: sdlTestFilterInput, status: sdlTestStatus = ACTIVE): [sdlTestNamed]
+}
+
+"""Status of a post"""
+enum sdlTestStatus {
+  ACTIVE
+  """Finished"""
+  DONE
+  OLD @deprecated(reason: "use \"DONE\"\n\u0000é")
+}
+`
+
+func TestPrintSchema(t *testing.T) {
+	sch := NewSchemaInfo()
+	sch.RegEnum(sdlTestStatus("")).SetDescription("Status of a post").
+		Value("ACTIVE", "active", "").
+		Value("DONE", "done", "Finished").
+		Value("OLD", "old", "").
+		Deprecate("OLD", "use \"DONE\"\n\x00é")
+	sch.RegInterface((*sdlTestNamed)(nil)).SetDescription("Something with a title")
+	sch.RegUnion((*sdlTestResult)(nil))
+	sch.RegType(&sdlTestPost{}).SetNonNode().SimpleFields()
+	sch.RegType(&sdlTestPerson{}).SetNonNode().SimpleFields()
+	sch.RegType(&sdlTestRoot{}).SetRoot().ResolvedFields().
+		AddField("old", &graphql.Field{Type: graphql.String, Description: "Old field\nsee search", DeprecationReason: "use search"}).
+		AddField("older", &graphql.Field{Type: graphql.String, DeprecationReason: graphql.DefaultDeprecationReason})
+	sdl, err := sch.GetSDL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sdl != sdlTestGolden {
+		t.Errorf("got SDL\n%s\nwant\n%s", sdl, sdlTestGolden)
+	}
+}
+
+func TestPrintString(t *testing.T) {
+	tests := []struct {
+		str  string
+		want string
+	}{
+		{"", `""`},
+		{"plain", `"plain"`},
+		{`say "hi" \o/`, `"say \"hi\" \\o/"`},
+		{"tab\tline\nreturn\rfeed\fback\b", `"tab\tline\nreturn\rfeed\fback\b"`},
+		{"nul\x00 esc\x1b del\x7f c1\u0085", `"nul\u0000 esc\u001B del\u007F c1\u0085"`},
+		{"é ü 日本 😀", `"é ü 日本 😀"`},
+	}
+	for _, test := range tests {
+		if got := printString(test.str); got != test.want {
+			t.Errorf("printString(%q) = %s, want %s", test.str, got, test.want)
+		}
+	}
+}
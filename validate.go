@@ -0,0 +1,547 @@
+package gographer
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validate checks all the registered types, fields, arguments and outputs,
+// returns SchemaErrors listing every problem found, or nil.
+func (sch *SchemaInfo) Validate() error {
+	if errs := sch.validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (sch *SchemaInfo) validate() SchemaErrors {
+	var errs SchemaErrors
+	hasRoot := false
+
+	for _, enum := range sch.enums {
+		errs = append(errs, enum.validate()...)
+	}
+	for _, iface := range sch.interfaces {
+		errs = append(errs, sch.validateInterface(iface)...)
+	}
+	for _, union := range sch.unions {
+		errs = append(errs, sch.validateUnion(union)...)
+	}
+
+	for _, typ := range sch.types {
+		errs = append(errs, typ.errors.withTypeName(typ.Name)...)
+
+		if typ.hasIDResolver() && !typ.hasNodeIDField() {
+			errs = append(errs, &SchemaError{TypeName: typ.Name, Message: "ID resolver needs an id field of type ID!, use IDField or SetNonNode"})
+		}
+		if typ.isRootType {
+			if hasRoot {
+				errs = append(errs, &SchemaError{TypeName: typ.Name, Message: "more than one root type registered"})
+			}
+			hasRoot = true
+		}
+
+		fieldNames := map[string]bool{"node": typ.isRootType, "nodes": typ.isRootType}
+		fieldMethods := make(map[string]string) // of resolved fields
+		for name := range typ.fields {
+			fieldNames[name] = true
+		}
+		for _, sf := range typ.simpleFields {
+			if sf.auto && !sch.includeAutoField(typ, sf.Name, sf.GoType) {
+				continue
+			}
+			fieldNames[sf.Name] = true
+			if msg := sch.checkQLType(sf.GoType, sf.Name, ""); msg != "" {
+				errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: sf.Name, Message: fmt.Sprintf("simple field type %v: %s", sf.GoType, msg)})
+			}
+		}
+		for _, rf := range typ.resolvedFields {
+			if rf.autoSimple && !sch.includeAutoField(typ, rf.Name, rf.ManualGoType) {
+				continue
+			}
+			if fieldNames[rf.Name] {
+				errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: rf.Name, Message: "field is defined more than once", keepField: true})
+				continue // left out when building, the first definition stays
+			}
+			fieldNames[rf.Name] = true
+			if rf.ExtensionFunc == nil {
+				fieldMethods[rf.Name] = rf.MethodName
+			}
+			errs = append(errs, sch.validateResolvedField(typ, rf)...)
+		}
+		for _, iface := range sch.interfaces {
+			if !typ.implements(iface.Type) {
+				continue
+			}
+			for _, rf := range iface.fields(sch) {
+				if fieldNames[rf.Name] && fieldMethods[rf.Name] != rf.MethodName {
+					errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: rf.Name, Message: fmt.Sprintf("field shadows method %s of interface %s", rf.MethodName, iface.Name), keepField: true})
+				}
+			}
+		}
+
+		mutationNames := make(map[string]bool)
+		for _, mf := range typ.mutationFields {
+			if mutationNames[mf.Name] {
+				errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: mf.Name, Message: "mutation is defined more than once", keepField: true})
+				continue
+			}
+			mutationNames[mf.Name] = true
+			errs = append(errs, sch.validateMutationField(typ, mf)...)
+		}
+	}
+
+	if !hasRoot {
+		errs = append(errs, &SchemaError{Message: "no root type registered, use SetRoot"})
+	}
+
+	errs = append(errs, sch.checkLossyInts()...)
+	errs = append(errs, sch.checkTypeNames()...)
+
+	return errs
+}
+
+func (sch *SchemaInfo) validateResolvedField(typ *TypeInfo, rf ResolvedFieldInfo) SchemaErrors {
+	var errs SchemaErrors
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: rf.Name, Message: fmt.Sprintf(format, a...)})
+	}
+
+	funcType, found := typ.resolvedFuncType(rf)
+	if !found {
+		if rf.ExtensionFunc != nil {
+			fail("extension func needs to be a function, got %T", rf.ExtensionFunc)
+		} else {
+			fail("cannot find method %s", rf.MethodName)
+		}
+		return errs
+	}
+
+	if rf.ExtensionFunc != nil {
+		if funcType.NumIn() == 0 {
+			fail("extension func needs the source object as first parameter")
+			return errs
+		}
+		sourceType := funcType.In(0)
+		if !reflect.PtrTo(typ.Type).AssignableTo(sourceType) && !typ.Type.AssignableTo(sourceType) {
+			fail("extension func's first parameter %v cannot accept %v", sourceType, typ.Type)
+		}
+	}
+
+	if numResultOut(funcType) != 1 {
+		fail("needs exactly one return value, optionally followed by an error, got %v", funcType)
+	} else if rf.ManualType == nil {
+		returnType := rf.returnGoType(funcType)
+		if isPageType(returnType) {
+			fail("return type %v needs the node type, use PagerField", returnType)
+		} else if funcType.Out(0) == thunkType && rf.ElemInterface == nil && rf.ManualGoType == nil {
+			fail("return type %v needs the node type, use LoaderField", funcType.Out(0))
+		} else if msg := sch.checkQLType(returnType, rf.Name, sch.nodeTypeName("", rf.ElemInterface)); msg != "" {
+			fail("return type %v: %s", returnType, msg)
+		} else if rf.IsConnection && rf.ManualType == nil {
+			if msg := sch.checkConnectionType(returnType); msg != "" {
+				fail("return type %v: %s", returnType, msg)
+			}
+		}
+	}
+
+	if hasPageArgs(funcType) && !rf.IsConnection {
+		fail("PageArgs parameter needs a connection field")
+	}
+	for _, msg := range sch.validateArgs(funcType, rf.AutoArgs, rf.Args) {
+		fail("%s", msg)
+	}
+	return errs
+}
+
+func (sch *SchemaInfo) validateMutationField(typ *TypeInfo, mf MutationFieldInfo) SchemaErrors {
+	var errs SchemaErrors
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, &SchemaError{TypeName: typ.Name, FieldName: mf.Name, Message: fmt.Sprintf(format, a...)})
+	}
+
+	method, found := typ.findMethod(mf.MethodName)
+	if !found {
+		fail("cannot find method %s", mf.MethodName)
+		return errs
+	}
+	funcType := method.Func.Type()
+
+	for _, msg := range sch.validateArgs(funcType, mf.AutoArgs, mf.Args) {
+		fail("%s", msg)
+	}
+
+	numOut := numResultOut(funcType)
+	if mf.AutoOutputs {
+		if numOut > 1 {
+			fail("AutoOutputs needs a single struct return value, got %v", funcType)
+		} else if numOut == 1 {
+			outStructType := funcType.Out(0)
+			if outStructType.Kind() == reflect.Ptr {
+				outStructType = outStructType.Elem()
+			}
+			if outStructType.Kind() != reflect.Struct {
+				fail("AutoOutputs needs a struct return value, got %v", funcType.Out(0))
+			} else {
+				for i := 0; i < outStructType.NumField(); i++ {
+					outField := outStructType.Field(i)
+					if _, ok := sch.fieldName(outField); !ok {
+						continue // unexported or excluded by its tag
+					}
+					if msg := sch.checkQLType(outField.Type, outField.Name, outField.Tag.Get(TAG_ElemType)); msg != "" {
+						fail("output field %s %v: %s", outField.Name, outField.Type, msg)
+					} else if outField.Tag.Get(TAG_Connection) == "true" {
+						if msg := sch.checkConnectionType(outField.Type); msg != "" {
+							fail("output field %s %v: %s", outField.Name, outField.Type, msg)
+						}
+					}
+				}
+			}
+		}
+	} else {
+		if len(mf.Outputs) != numOut {
+			fail("%d OutputInfo given but method returns %d values", len(mf.Outputs), numOut)
+		} else {
+			for i := 0; i < numOut; i++ {
+				if msg := sch.checkQLType(funcType.Out(i), mf.Outputs[i].Name, sch.nodeTypeName(mf.Outputs[i].ElemTypeName, mf.Outputs[i].ElemInterface)); msg != "" {
+					fail("output %s %v: %s", mf.Outputs[i].Name, funcType.Out(i), msg)
+				} else if mf.Outputs[i].IsConnection {
+					if msg := sch.checkConnectionType(funcType.Out(i)); msg != "" {
+						fail("output %s %v: %s", mf.Outputs[i].Name, funcType.Out(i), msg)
+					}
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func (sch *SchemaInfo) validateInterface(iface *InterfaceInfo) SchemaErrors {
+	errs := iface.errors.withTypeName(iface.Name)
+	if len(errs) > 0 {
+		return errs
+	}
+	fields := iface.fields(sch)
+	if len(fields) == 0 {
+		errs = append(errs, &SchemaError{TypeName: iface.Name, Message: "interface has no exported methods"})
+	}
+	for _, rf := range fields {
+		fail := func(format string, a ...interface{}) {
+			errs = append(errs, &SchemaError{TypeName: iface.Name, FieldName: rf.Name, Message: fmt.Sprintf(format, a...)})
+		}
+		funcType := iface.methodType(rf.MethodName)
+		if numResultOut(funcType) != 1 {
+			fail("needs exactly one return value, optionally followed by an error, got %v", funcType)
+		} else if msg := sch.checkQLType(funcType.Out(0), rf.Name, ""); msg != "" {
+			fail("return type %v: %s", funcType.Out(0), msg)
+		} else if rf.IsConnection {
+			if msg := sch.checkConnectionType(funcType.Out(0)); msg != "" {
+				fail("return type %v: %s", funcType.Out(0), msg)
+			}
+		}
+		if hasPageArgs(funcType) && !rf.IsConnection {
+			fail("PageArgs parameter needs a connection field")
+		}
+		for _, msg := range sch.validateArgs(funcType, rf.AutoArgs, rf.Args) {
+			fail("%s", msg)
+		}
+	}
+	return errs
+}
+
+func (sch *SchemaInfo) validateUnion(union *UnionInfo) SchemaErrors {
+	errs := union.errors.withTypeName(union.Name)
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, memberType := range union.members {
+		if _, ok := sch.typesByType[memberType]; !ok {
+			errs = append(errs, &SchemaError{TypeName: union.Name, Message: fmt.Sprintf("member type %v is not registered, use RegType", memberType)})
+		} else if !reflect.PtrTo(memberType).Implements(union.Type) {
+			errs = append(errs, &SchemaError{TypeName: union.Name, Message: fmt.Sprintf("member type %v doesn't implement %v", memberType, union.Type)})
+		}
+	}
+	if len(errs) == 0 && len(sch.unionMembers(union)) == 0 {
+		errs = append(errs, &SchemaError{TypeName: union.Name, Message: "union has no member types"})
+	}
+	return errs
+}
+
+// Check method or extension func parameters against AutoArgs or manual ArgInfo.
+func (sch *SchemaInfo) validateArgs(funcType reflect.Type, autoArgs bool, args []ArgInfo) []string {
+	var msgs []string
+	argIndex := firstArgIndex(funcType)
+	numArgs := numArgIn(funcType) - argIndex
+	if numArgs < 0 {
+		numArgs = 0
+	}
+
+	if autoArgs {
+		if numArgs > 1 {
+			msgs = append(msgs, fmt.Sprintf("AutoArgs needs a single struct parameter, got %d parameters", numArgs))
+		} else if numArgs == 1 {
+			argStructType := funcType.In(argIndex)
+			if argStructType.Kind() != reflect.Struct {
+				msgs = append(msgs, fmt.Sprintf("AutoArgs needs a struct parameter, got %v", argStructType))
+			} else {
+				for i := 0; i < argStructType.NumField(); i++ {
+					argField := argStructType.Field(i)
+					if _, ok := sch.fieldName(argField); !ok && argField.PkgPath == "" {
+						continue // excluded by its tag
+					}
+					if argField.PkgPath != "" {
+						msgs = append(msgs, fmt.Sprintf("argument field %s needs to be exported", argField.Name))
+					} else if msg := sch.checkGlobalIDField(argField); msg != "" {
+						msgs = append(msgs, fmt.Sprintf("argument field %s: %s", argField.Name, msg))
+					} else if msg := sch.checkInputType(argField.Type); msg != "" {
+						msgs = append(msgs, fmt.Sprintf("argument field %s %v: %s", argField.Name, argField.Type, msg))
+					} else if defTag := argField.Tag.Get(TAG_DefaultValue); defTag != "" && sch.parseDefaultValue(defTag, argField.Type) == nil {
+						msgs = append(msgs, fmt.Sprintf("invalid default value %q for argument field %s %v", defTag, argField.Name, argField.Type))
+					}
+				}
+			}
+		}
+	} else {
+		if len(args) != numArgs {
+			msgs = append(msgs, fmt.Sprintf("%d ArgInfo given but function takes %d arguments", len(args), numArgs))
+		} else {
+			for i := 0; i < numArgs; i++ {
+				if msg := sch.checkInputType(funcType.In(argIndex + i)); msg != "" {
+					msgs = append(msgs, fmt.Sprintf("argument %s %v: %s", args[i].Name, funcType.In(argIndex+i), msg))
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// Check the Go type can be used as argument or input field, returns the problem or an empty string.
+func (sch *SchemaInfo) checkInputType(typ reflect.Type) string {
+	return sch.checkInputTypeVisited(typ, make(map[reflect.Type]bool))
+}
+
+func (sch *SchemaInfo) checkInputTypeVisited(typ reflect.Type, visited map[reflect.Type]bool) string {
+	if scalar, ok := sch.scalarsByType[typ]; ok {
+		if scalar.parseValue == nil || scalar.parseLiteral == nil {
+			return fmt.Sprintf("scalar %s doesn't support input", scalar.Name)
+		}
+		return ""
+	}
+	if sch.hasNamedQLType(typ) {
+		return ""
+	}
+	if valueField, ok := optionalValueField(typ); ok {
+		return sch.checkInputTypeVisited(valueField.Type, visited)
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Ptr:
+		return sch.checkInputTypeVisited(typ.Elem(), visited)
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return "anonymous struct cannot be used as input type"
+		}
+		name := sch.inputTypeName(typ)
+		if info, ok := sch.inputObjects[name]; !ok {
+			sch.inputObjects[name] = &inputObjectInfo{Type: typ} // claim the name, built by inputObjectType
+		} else if info.Type != typ {
+			return fmt.Sprintf("input type %s is already used for %v", name, info.Type)
+		}
+		if visited[typ] {
+			return ""
+		}
+		visited[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			fieldName, ok := sch.fieldName(field)
+			if !ok {
+				continue
+			}
+			if msg := sch.checkGlobalIDField(field); msg != "" {
+				return fmt.Sprintf("%s.%s: %s", name, fieldName, msg)
+			}
+			if msg := sch.checkInputTypeVisited(field.Type, visited); msg != "" {
+				return fmt.Sprintf("%s.%s: %s", name, fieldName, msg)
+			}
+			if defTag := field.Tag.Get(TAG_DefaultValue); defTag != "" && sch.parseDefaultValue(defTag, field.Type) == nil {
+				return fmt.Sprintf("%s.%s: invalid default value %q", name, fieldName, defTag)
+			}
+		}
+		return ""
+	}
+	if sch.toQLType(typ) == nil {
+		return "cannot resolve GraphQL type"
+	}
+	return ""
+}
+
+// Check a field tagged with TAG_GlobalID, returns the problem or an empty string.
+func (sch *SchemaInfo) checkGlobalIDField(field reflect.StructField) string {
+	globalIDTag := field.Tag.Get(TAG_GlobalID)
+	if globalIDTag == "" {
+		return ""
+	}
+	if !isGlobalIDFieldType(field.Type) {
+		return fmt.Sprintf("%s tag needs a string or []string field, got %v", TAG_GlobalID, field.Type)
+	}
+	if _, ok := sch.typesByName[globalIDTag]; !ok {
+		return fmt.Sprintf("%s type %s is not registered, use RegType", TAG_GlobalID, globalIDTag)
+	}
+	return ""
+}
+
+// Check a connection field returns a slice of a registered object type or a relay.Connection,
+// Relay connections can't page scalars, interfaces or unions. Returns the problem or an empty string.
+func (sch *SchemaInfo) checkConnectionType(returnType reflect.Type) string {
+	if returnType == relayConnectionType || returnType == reflect.PtrTo(relayConnectionType) {
+		return "" // node type checked by checkQLType
+	}
+	if returnType.Kind() != reflect.Slice {
+		return "connection needs a slice return type"
+	}
+	elemType := returnType.Elem()
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if _, ok := sch.typesByType[elemType]; !ok || sch.toQLType(elemType) != nil {
+		return fmt.Sprintf("connection node type %v is not a registered object type", elemType)
+	}
+	return ""
+}
+
+// Check getComplexQLType will be able to resolve a GraphQL type for the Go type,
+// returns the problem or an empty string. Registration order doesn't matter.
+// elemTypeName is the explicit node type of edges and connections.
+func (sch *SchemaInfo) checkQLType(returnType reflect.Type, fieldName string, elemTypeName string) string {
+	elemType := returnType
+	if elemType.Kind() == reflect.Slice || elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+	}
+	if sch.toQLType(elemType) != nil {
+		return ""
+	}
+	if elemType == edgeType || elemType == relayConnectionType {
+		if elemTypeName == "" {
+			elemTypeName = inferTypeNameFromField(fieldName)
+		}
+		if elemTypeName == "" {
+			return "cannot infer node type, use the elemType tag or ElemInterface, or name the field <Type>Edge or <Type>Connection"
+		}
+		if typ, ok := sch.typesByName[elemTypeName]; !ok || typ.isRootType || typ.isMutationType {
+			return fmt.Sprintf("node type %s is not registered, use RegType", elemTypeName)
+		}
+		return ""
+	}
+	if _, ok := sch.interfacesByType[elemType]; ok {
+		return ""
+	}
+	if _, ok := sch.unionsByType[elemType]; ok {
+		return ""
+	}
+	if typ, ok := sch.typesByType[elemType]; ok {
+		if typ.isMutationType {
+			return fmt.Sprintf("type %s is the mutation type and cannot be used as a field type", typ.Name)
+		}
+		return ""
+	}
+	if elemType.Kind() == reflect.Struct {
+		return fmt.Sprintf("type %s is not registered, use RegType", elemType.Name())
+	}
+	if elemType.Kind() == reflect.Interface {
+		return fmt.Sprintf("interface %v is not registered, use RegInterface or RegUnion", elemType)
+	}
+	return "cannot resolve GraphQL type"
+}
+
+// Report wide integers mapped to 32 bit Int, the fields are built unless in strict mode.
+func (sch *SchemaInfo) checkLossyInts() SchemaErrors {
+	var errs SchemaErrors
+	report := func(typeName, fieldName, what string) {
+		msg := fmt.Sprintf("%s is mapped to 32 bit Int, use SetWideIntMapping", what)
+		errs = append(errs, &SchemaError{TypeName: typeName, FieldName: fieldName, Message: msg, keepField: true})
+	}
+	checkArgs := func(typeName, fieldName string, funcType reflect.Type, autoArgs bool, args []ArgInfo) {
+		var argTypes []reflect.Type
+		var argNames []string
+		for i := firstArgIndex(funcType); i < numArgIn(funcType); i++ {
+			if argStructType := funcType.In(i); autoArgs && argStructType.Kind() == reflect.Struct {
+				for j := 0; j < argStructType.NumField(); j++ {
+					if argName, ok := sch.fieldName(argStructType.Field(j)); ok {
+						argTypes = append(argTypes, argStructType.Field(j).Type)
+						argNames = append(argNames, argName)
+					}
+				}
+			} else if argIndex := i - firstArgIndex(funcType); argIndex < len(args) {
+				argTypes = append(argTypes, funcType.In(i))
+				argNames = append(argNames, args[argIndex].Name)
+			}
+		}
+		visited := make(map[reflect.Type]bool)
+		for i, argType := range argTypes {
+			if sch.isLossyInt(argType) {
+				report(typeName, fieldName, fmt.Sprintf("argument %s %v", argNames[i], argType))
+			}
+			for _, inputField := range sch.lossyInputFields(argType, visited) {
+				report(typeName, fieldName, "input field "+inputField)
+			}
+		}
+	}
+
+	for _, typ := range sch.types {
+		for _, sf := range typ.simpleFields {
+			if sf.auto && !sch.includeAutoField(typ, sf.Name, sf.GoType) {
+				continue
+			}
+			if sch.isLossyInt(sf.GoType) {
+				report(typ.Name, sf.Name, fmt.Sprintf("field type %v", sf.GoType))
+			}
+		}
+		for _, rf := range typ.resolvedFields {
+			if rf.autoSimple && !sch.includeAutoField(typ, rf.Name, rf.ManualGoType) {
+				continue
+			}
+			funcType, found := typ.resolvedFuncType(rf)
+			if !found || numResultOut(funcType) != 1 {
+				continue // reported by validateResolvedField
+			}
+			returnType := funcType.Out(0)
+			if rf.ManualGoType != nil {
+				returnType = rf.ManualGoType
+			}
+			if rf.ManualType == nil && sch.isLossyInt(returnType) {
+				report(typ.Name, rf.Name, fmt.Sprintf("return type %v", returnType))
+			}
+			checkArgs(typ.Name, rf.Name, funcType, rf.AutoArgs, rf.Args)
+		}
+		for _, mf := range typ.mutationFields {
+			method, found := typ.findMethod(mf.MethodName)
+			if !found {
+				continue
+			}
+			funcType := method.Func.Type()
+			checkArgs(typ.Name, mf.Name, funcType, mf.AutoArgs, mf.Args)
+			for i := 0; i < numResultOut(funcType); i++ {
+				outType := funcType.Out(i)
+				if mf.AutoOutputs {
+					if outType.Kind() == reflect.Ptr {
+						outType = outType.Elem()
+					}
+					if outType.Kind() != reflect.Struct {
+						continue
+					}
+					for j := 0; j < outType.NumField(); j++ {
+						outField := outType.Field(j)
+						if _, ok := sch.fieldName(outField); ok && sch.isLossyInt(outField.Type) {
+							report(typ.Name, mf.Name, fmt.Sprintf("output field %s %v", outField.Name, outField.Type))
+						}
+					}
+				} else if sch.isLossyInt(outType) {
+					report(typ.Name, mf.Name, fmt.Sprintf("output %v", outType))
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,164 @@
+package gographer
+
+import (
+	"encoding/json"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/relay"
+	"strings"
+	"testing"
+)
+
+// JSON of the result of the query, with its data and errors.
+func resultJSON(t *testing.T, schema graphql.Schema, query string, variables map[string]interface{}) string {
+	t.Helper()
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: query, VariableValues: variables})
+	b, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+type validateTestItem struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+func (item *validateTestItem) GetOther() string {
+	return "other"
+}
+
+func (item *validateTestItem) GetArgs(a int) string {
+	return "args"
+}
+
+type validateTestRoot struct{}
+
+func (r *validateTestRoot) GetItem() *validateTestItem {
+	return &validateTestItem{Name: "name", Title: "title"}
+}
+
+func (r *validateTestRoot) GetPage() *Page {
+	return nil
+}
+
+type validateTestUnregistered struct{}
+
+type validateTestMutation struct{}
+
+func (m *validateTestMutation) First() (string, error) {
+	return "first", nil
+}
+
+func (m *validateTestMutation) Second() (string, error) {
+	return "second", nil
+}
+
+type validateTestEdgeOutput struct {
+	ItemEdge relay.EdgeType `json:"itemEdge" elemType:"validateTestMissing"`
+}
+
+func (m *validateTestMutation) AddItem() *validateTestEdgeOutput {
+	return nil
+}
+
+func newValidateTestSchema(strict bool) *SchemaInfo {
+	sch := NewSchemaInfo().SetStrict(strict)
+	sch.RegType(&validateTestRoot{}).SetRoot().
+		ResolvedField("item", "GetItem", nil).
+		PagerField("page", "GetPage", &validateTestUnregistered{}, nil)
+	sch.RegType(&validateTestItem{}).SetNonNode().
+		SimpleField("name").
+		SimpleField("name").
+		SimpleField("title").
+		AddField("title", &graphql.Field{Type: graphql.String}).
+		ResolvedField("title", "GetOther", nil).
+		ResolvedField("other", "GetOther", nil).
+		ResolvedField("other", "GetArgs", []ArgInfo{{Name: "a"}}).
+		ResolvedField("args", "GetArgs", nil).
+		ResolvedField("missing", "GetMissing", nil)
+	sch.RegType(&validateTestMutation{}).SetMutation().
+		MutationField("run", "First", nil, []OutputInfo{{Name: "result"}}).
+		MutationField("run", "Second", nil, []OutputInfo{{Name: "result"}}).
+		MutationField("addItem", "AddItem", nil, AutoOutputs)
+	return sch
+}
+
+func TestValidate(t *testing.T) {
+	err := newValidateTestSchema(false).Validate()
+	want := []string{
+		"validateTestRoot.page: return type []gographer.validateTestUnregistered: type validateTestUnregistered is not registered, use RegType",
+		"validateTestItem.name: field is defined more than once",
+		"validateTestItem.title: field is defined more than once",
+		"validateTestItem.title: field is defined more than once",
+		"validateTestItem.other: field is defined more than once",
+		"validateTestItem.args: 0 ArgInfo given but function takes 1 arguments",
+		"validateTestItem.missing: cannot find method GetMissing",
+		"validateTestMutation.run: mutation is defined more than once",
+		"validateTestMutation.addItem: output field ItemEdge relay.EdgeType: node type validateTestMissing is not registered, use RegType",
+	}
+	errs, ok := err.(SchemaErrors)
+	if !ok {
+		t.Fatalf("got %v, want SchemaErrors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	for _, msg := range want {
+		found := false
+		for i, gotMsg := range got {
+			if gotMsg == msg {
+				got = append(got[:i], got[i+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing error %q", msg)
+		}
+	}
+	if len(got) > 0 {
+		t.Errorf("unexpected errors %q", got)
+	}
+	wantMessage := "gographer: 9 schema registration error(s):\n\t" + errs[0].Error()
+	if msg := err.Error(); !strings.HasPrefix(msg, wantMessage) || strings.Count(msg, "\n\t") != 9 {
+		t.Errorf("got message %q", msg)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+	schema, err := newValidateTestSchema(true).GetSchema()
+	if _, ok := err.(SchemaErrors); !ok || schema.QueryType() != nil {
+		t.Errorf("strict schema built with errors %v", err)
+	}
+}
+
+func TestLenientSchema(t *testing.T) {
+	schema, err := newValidateTestSchema(false).GetSchema()
+	if _, ok := err.(SchemaErrors); !ok || schema.QueryType() == nil {
+		t.Fatalf("lenient schema not built, got errors %v", err)
+	}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		// the first definition of a field is kept, later ones are skipped
+		{`{ item { name title other } }`, `{"data":{"item":{"name":"name","other":"other","title":"title"}}}`},
+		{`{ item { args } }`, `{"data":null,"errors":[{"message":"Cannot query field \"args\" on type \"validateTestItem\".","locations":[{"line":1,"column":10}]}]}`},
+		{`{ page { edges { cursor } } }`, `{"data":null,"errors":[{"message":"Cannot query field \"page\" on type \"validateTestRoot\".","locations":[{"line":1,"column":3}]}]}`},
+	}
+	for _, test := range tests {
+		if got := resultJSON(t, schema, test.query, nil); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.query, got, test.want)
+		}
+	}
+}
+
+func TestDuplicateMutations(t *testing.T) {
+	schema, _ := newValidateTestSchema(false).GetSchema()
+	want := `{"data":{"run":{"result":"first"}}}`
+	if got := resultJSON(t, schema, `mutation { run(input: {clientMutationId: "1"}) { result } }`, nil); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
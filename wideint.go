@@ -0,0 +1,221 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// IntMapping is how integer kinds wider than 32 bit (int64, uint, uint32, uint64) map to GraphQL,
+// see SchemaInfo.SetWideIntMapping. int keeps mapping to Int.
+type IntMapping string
+
+const (
+	IntMapping_Int    IntMapping = "Int"    // GraphQL Int, 32 bit, values out of range become null
+	IntMapping_Long   IntMapping = "Long"   // Long scalar, serialized as JSON number
+	IntMapping_BigInt IntMapping = "BigInt" // BigInt scalar, serialized as decimal string
+	IntMapping_String IntMapping = "String" // GraphQL String, decimal string
+)
+
+var (
+	int64Type  = reflect.TypeOf(int64(0))
+	uint64Type = reflect.TypeOf(uint64(0))
+)
+
+func isWideInt(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// Long accepts numbers and decimal strings, clients should send big values as strings,
+// JSON numbers beyond 2^53 lose precision when decoded as float64.
+var LongScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "64 bit integer, serialized as number",
+	Serialize: func(value interface{}) interface{} {
+		return serializeWideInt(value, false)
+	},
+	ParseValue:   parseWideInt,
+	ParseLiteral: parseWideIntLiteral,
+})
+
+var BigIntScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "BigInt",
+	Description: "64 bit integer, serialized as decimal string",
+	Serialize: func(value interface{}) interface{} {
+		return serializeWideInt(value, true)
+	},
+	ParseValue:   parseWideInt,
+	ParseLiteral: parseWideIntLiteral,
+})
+
+func (sch *SchemaInfo) wideIntQLType() graphql.Output {
+	switch sch.wideInts {
+	case IntMapping_Long:
+		return LongScalar
+	case IntMapping_BigInt:
+		return BigIntScalar
+	case IntMapping_String:
+		return graphql.String
+	default:
+		return graphql.Int
+	}
+}
+
+// int64 or uint64 value of any integer, or its decimal string.
+func serializeWideInt(value interface{}, asString bool) interface{} {
+	val := reflect.Indirect(reflect.ValueOf(value))
+	var out interface{}
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out = val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		out = val.Uint()
+	default:
+		return nil
+	}
+	if asString {
+		return fmt.Sprint(out)
+	}
+	return out
+}
+
+// int64, or uint64 beyond the int64 range, from numbers and decimal strings.
+func parseWideInt(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return parseWideIntString(value)
+	case float64:
+		n, err := convertInt(reflect.ValueOf(value), int64Type)
+		if err != nil {
+			if n, err = convertInt(reflect.ValueOf(value), uint64Type); err != nil {
+				return nil
+			}
+		}
+		return n.Interface()
+	default:
+		val := reflect.ValueOf(value)
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return val.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return parseWideIntString(strconv.FormatUint(val.Uint(), 10))
+		case reflect.String: // json.Number
+			return parseWideIntString(val.String())
+		}
+	}
+	return nil
+}
+
+func parseWideIntString(str string) interface{} {
+	if v, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseUint(str, 10, 64); err == nil {
+		return v
+	}
+	return nil
+}
+
+func parseWideIntLiteral(valueAST ast.Value) interface{} {
+	switch valueAST := valueAST.(type) {
+	case *ast.IntValue:
+		return parseWideIntString(valueAST.Value)
+	case *ast.StringValue:
+		return parseWideIntString(valueAST.Value)
+	}
+	return nil
+}
+
+// Whether the Go type maps to GraphQL Int but holds values beyond 32 bit.
+func (sch *SchemaInfo) isLossyInt(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		if sch.hasNamedQLType(typ) {
+			return false
+		}
+		typ = typ.Elem()
+	}
+	if valueField, ok := optionalValueField(typ); ok {
+		return sch.isLossyInt(valueField.Type)
+	}
+	return isWideInt(typ.Kind()) && !sch.hasNamedQLType(typ) && sch.wideIntQLType() == graphql.Int
+}
+
+// Fields of an input struct with lossy integer mappings, including nested input objects.
+func (sch *SchemaInfo) lossyInputFields(typ reflect.Type, visited map[reflect.Type]bool) []string {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || sch.hasNamedQLType(typ) || visited[typ] {
+		return nil
+	}
+	if _, ok := optionalValueField(typ); ok {
+		return nil
+	}
+	visited[typ] = true
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldName, ok := sch.fieldName(field)
+		if !ok {
+			continue
+		}
+		if sch.isLossyInt(field.Type) {
+			names = append(names, fmt.Sprintf("%s.%s %v", sch.inputTypeName(typ), fieldName, field.Type))
+		}
+		names = append(names, sch.lossyInputFields(field.Type, visited)...)
+	}
+	return names
+}
+
+// Integer of type t from a number or decimal string input value, range checked.
+func convertInt(val reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	n, ok := new(big.Int), false
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok = n.SetInt64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok = n.SetUint64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		f := val.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+			return out, fmt.Errorf("%v is not an integer", f)
+		}
+		n, _ = big.NewFloat(f).Int(n)
+		ok = true
+	case reflect.String:
+		n, ok = n.SetString(val.String(), 10)
+	}
+	if !ok {
+		return out, fmt.Errorf("cannot use %v as %v", val.Interface(), t)
+	}
+	if isUintKind(t.Kind()) {
+		if !n.IsUint64() || out.OverflowUint(n.Uint64()) {
+			return out, fmt.Errorf("%v overflows %v", n, t)
+		}
+		out.SetUint(n.Uint64())
+	} else {
+		if !n.IsInt64() || out.OverflowInt(n.Int64()) {
+			return out, fmt.Errorf("%v overflows %v", n, t)
+		}
+		out.SetInt(n.Int64())
+	}
+	return out, nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uint64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uint64
+}
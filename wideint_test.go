@@ -0,0 +1,138 @@
+package gographer
+
+import (
+	"fmt"
+	"github.com/graphql-go/graphql"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertInt(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		typ   interface{}
+		want  interface{} // nil if the value is rejected
+		err   string
+	}{
+		{int64(42), int8(0), int8(42), ""},
+		{int64(128), int8(0), nil, "overflows"},
+		{int64(-1), uint32(0), nil, "overflows"},
+		{int64(math.MaxInt64), int64(0), int64(math.MaxInt64), ""},
+		{uint64(math.MaxUint64), uint64(0), uint64(math.MaxUint64), ""},
+		{uint64(math.MaxUint64), int64(0), nil, "overflows"},
+		{"18446744073709551615", uint64(0), uint64(math.MaxUint64), ""},
+		{"18446744073709551616", uint64(0), nil, "overflows"},
+		{"-9223372036854775809", int64(0), nil, "overflows"},
+		{"1.5", int64(0), nil, "cannot use"},
+		{"x", int(0), nil, "cannot use"},
+		{float64(3), uint(0), uint(3), ""},
+		{float64(-3), int32(0), int32(-3), ""},
+		{float64(1.5), int64(0), nil, "not an integer"},
+		{math.NaN(), int64(0), nil, "not an integer"},
+		{math.Inf(1), uint64(0), nil, "not an integer"},
+		{math.Inf(-1), int64(0), nil, "not an integer"},
+		{float64(1e19), int64(0), nil, "overflows"},
+		{float64(-1), uint64(0), nil, "overflows"},
+		{float32(1 << 31), int32(0), nil, "overflows"},
+	}
+	for _, test := range tests {
+		got, err := convertInt(reflect.ValueOf(test.value), reflect.TypeOf(test.typ))
+		if test.want == nil {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("convertInt(%v, %T): got %v, %v, want error %q", test.value, test.typ, got, err, test.err)
+			}
+			continue
+		}
+		if err != nil || got.Interface() != test.want {
+			t.Errorf("convertInt(%v, %T): got %v, %v, want %v", test.value, test.typ, got, err, test.want)
+		}
+	}
+}
+
+func TestParseWideInt(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  interface{} // nil if rejected
+	}{
+		{float64(42), int64(42)},
+		{float64(-42), int64(-42)},
+		{float64(1e19), uint64(1e19)},
+		{float64(1e20), nil},
+		{float64(-1e19), nil},
+		{1.5, nil},
+		{math.NaN(), nil},
+		{math.Inf(1), nil},
+		{math.Inf(-1), nil},
+		{"18446744073709551615", uint64(math.MaxUint64)},
+		{"-9223372036854775808", int64(math.MinInt64)},
+		{"18446744073709551616", nil},
+		{"1.0", nil},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{int32(-7), int64(-7)},
+	}
+	for _, test := range tests {
+		if got := parseWideInt(test.value); got != test.want {
+			t.Errorf("parseWideInt(%v) = %#v, want %#v", test.value, got, test.want)
+		}
+	}
+}
+
+type wideIntTestRoot struct{}
+
+type wideIntTestArgs struct {
+	Value uint32 `json:"value"`
+}
+
+func (r *wideIntTestRoot) Echo(args wideIntTestArgs) string {
+	return fmt.Sprint(args.Value)
+}
+
+func TestWideIntArgs(t *testing.T) {
+	tests := []struct {
+		mapping IntMapping
+		value   interface{}
+		want    string // echoed value, empty if rejected
+	}{
+		{IntMapping_Int, 7, "7"},
+		{IntMapping_Int, -1, ""},
+		{IntMapping_Long, 1.5, ""},
+		{IntMapping_Long, 4294967295, "4294967295"},
+		{IntMapping_Long, "4294967295", "4294967295"},
+		{IntMapping_Long, 4294967296, ""},
+		{IntMapping_Long, "18446744073709551616", ""},
+		{IntMapping_BigInt, "4294967295", "4294967295"},
+		{IntMapping_BigInt, "-1", ""},
+		{IntMapping_String, "4294967295", "4294967295"},
+		{IntMapping_String, "4294967296", ""},
+		{IntMapping_String, "1e3", ""},
+	}
+	for _, test := range tests {
+		sch := NewSchemaInfo().SetWideIntMapping(test.mapping)
+		sch.RegType(&wideIntTestRoot{}).SetRoot().ResolvedField("echo", "Echo", AutoArgs)
+		schema, err := sch.GetSchema()
+		if test.mapping == IntMapping_Int {
+			// reported, the field is still built
+			if !hasSchemaError(err, "wideIntTestRoot", "argument value uint32 is mapped to 32 bit Int") || schema.QueryType() == nil {
+				t.Fatalf("lossy mapping: got %v", err)
+			}
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  fmt.Sprintf(`query($v: %s) { echo(value: $v) }`, test.mapping),
+			VariableValues: map[string]interface{}{"v": test.value},
+		})
+		if test.want == "" {
+			if len(result.Errors) == 0 {
+				t.Errorf("%s %v: accepted as %v", test.mapping, test.value, result.Data)
+			}
+			continue
+		}
+		if len(result.Errors) > 0 || result.Data.(map[string]interface{})["echo"] != test.want {
+			t.Errorf("%s %v: got %v %v, want %s", test.mapping, test.value, result.Data, result.Errors, test.want)
+		}
+	}
+}